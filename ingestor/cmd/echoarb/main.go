@@ -0,0 +1,81 @@
+// cmd/echoarb/main.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/config"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(64)
+	}
+
+	switch os.Args[1] {
+	case "config":
+		runConfig(os.Args[2:])
+	default:
+		usage()
+		os.Exit(64)
+	}
+}
+
+func runConfig(args []string) {
+	if len(args) < 1 {
+		usage()
+		os.Exit(64)
+	}
+
+	switch args[0] {
+	case "test-credentials":
+		testCredentials()
+	default:
+		usage()
+		os.Exit(64)
+	}
+}
+
+// testCredentials loads the process config and drives
+// config.TestKalshiCredentials end-to-end, exiting with a distinct non-zero
+// code per error class so this can be wired into a deploy pipeline ahead of
+// promoting a new key.
+func testCredentials() {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		os.Exit(64)
+	}
+
+	if err := config.TestKalshiCredentials(cfg); err != nil {
+		var credErr *config.CredentialError
+		if errors.As(err, &credErr) {
+			fmt.Fprintf(os.Stderr, "kalshi credentials check failed (%s): %v\n", credErr.Kind, credErr.Err)
+			os.Exit(exitCodeFor(credErr.Kind))
+		}
+		fmt.Fprintf(os.Stderr, "kalshi credentials check failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Kalshi credentials OK")
+}
+
+func exitCodeFor(kind config.CredentialErrorKind) int {
+	switch kind {
+	case config.KeyDecodeError:
+		return 3
+	case config.NetworkError:
+		return 2
+	case config.InvalidCredentials:
+		return 1
+	default:
+		return 1
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: echoarb config test-credentials")
+}
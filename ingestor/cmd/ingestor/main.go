@@ -10,13 +10,14 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/dragonuber/echoarb/ingestor/internal/auditlog"
 	"github.com/dragonuber/echoarb/ingestor/internal/config"
 	"github.com/dragonuber/echoarb/ingestor/internal/connectors"
 	"github.com/dragonuber/echoarb/ingestor/internal/metrics"
-	"github.com/dragonuber/echoarb/ingestor/internal/models"
 	"github.com/dragonuber/echoarb/ingestor/internal/redis"
-	
+	"github.com/dragonuber/echoarb/ingestor/internal/shm"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
 	"go.uber.org/zap"
 )
 
@@ -37,9 +38,19 @@ func main() {
 		sugar.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Verify every external dependency is actually reachable before starting
+	// connectors, instead of finding out one at a time as they crash-loop.
+	preflightCtx, preflightCancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	report := cfg.Preflight(preflightCtx)
+	preflightCancel()
+	sugar.Infof("Preflight report:\n%s", report.String())
+	if !report.OK() {
+		sugar.Fatalf("Preflight checks failed, refusing to start")
+	}
+
 	// Initialize metrics
 	metricsRegistry := metrics.NewRegistry()
-	
+
 	// Start metrics server
 	go startMetricsServer(cfg.MetricsPort, sugar)
 
@@ -50,38 +61,104 @@ func main() {
 	}
 	defer redisClient.Close()
 
-	// Create tick channel (Buffer 1000 to handle bursts)
-	msgChan := make(chan models.Tick, 1000)
+	// Initialize the shared-memory ring buffer for co-located consumers, if
+	// enabled. It's a latency optimization, not a requirement, so a failure
+	// here is logged and ignored rather than fatal - ticks still reach
+	// consumers via Redis.
+	var shmWriter *shm.Writer
+	if cfg.Shm.Enabled {
+		shmWriter, err = shm.NewWriter(cfg.Shm.Path, cfg.Shm.RingBytes)
+		if err != nil {
+			sugar.Errorf("Failed to initialize shared-memory ring buffer, continuing without it: %v", err)
+			shmWriter = nil
+		} else {
+			defer shmWriter.Close()
+		}
+	}
 
-	// Initialize connectors
-	// Note: We pass sugar (Logger) and msgChan. No Redis client needed here.
-	kalshiConn := connectors.NewKalshiConnector(cfg, sugar, msgChan)
-	polyConn := connectors.NewPolymarketConnector(cfg, sugar, msgChan)
+	// Initialize the audit log (a no-op if disabled). It's shared across
+	// every SubscriptionGroup - it's a debugging/forensics aid, not part of
+	// the per-tenant data path SubscriptionGroup.RedisPrefix isolates.
+	auditLogger, err := auditlog.New(cfg.AuditLog)
+	if err != nil {
+		sugar.Fatalf("Failed to initialize audit log: %v", err)
+	}
+	defer auditLogger.Close()
+
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	defer cancelRun()
+
+	// Initialize one Kalshi/Polymarket connector pair per SubscriptionGroup.
+	// Each group gets its own resolved Kalshi credentials and its own Redis
+	// key namespace (via redisClient.ForGroup), so independent tenants
+	// sharing this process never cross-publish or cross-subscribe.
+	type groupConnectors struct {
+		kalshi   *connectors.KalshiConnector
+		poly     *connectors.PolymarketConnector
+		manifold *connectors.ManifoldConnector
+	}
+	byGroup := make(map[string]groupConnectors, len(cfg.Groups))
 
-	// Start connectors
 	sugar.Info("Starting connectors...")
+	for _, group := range cfg.Groups {
+		groupLogger := sugar.Named(group.Name)
+		groupRedis := redisClient.ForGroup(group.RedisPrefix)
 
-	// Kalshi
+		kalshiConn, err := connectors.NewKalshiConnector(runCtx, cfg, group, groupRedis, shmWriter, auditLogger, metricsRegistry, groupLogger)
+		if err != nil {
+			sugar.Fatalf("Failed to initialize Kalshi connector for group %q: %v", group.Name, err)
+		}
+		polyConn := connectors.NewPolymarketConnector(cfg, group, groupRedis, shmWriter, auditLogger, metricsRegistry, groupLogger)
+		manifoldConn := connectors.NewManifoldConnector(cfg, group, groupRedis, metricsRegistry, groupLogger)
+
+		byGroup[group.Name] = groupConnectors{kalshi: kalshiConn, poly: polyConn, manifold: manifoldConn}
+
+		go kalshiConn.Start(runCtx)
+		go polyConn.Start(runCtx)
+		go manifoldConn.Start(runCtx)
+
+		// Poll for a rotated Kalshi key pair (Vault lease renewal, a
+		// rewritten AWS Secrets Manager secret, ...) and push it into the
+		// live connector without a restart.
+		go func(group config.SubscriptionGroup, kalshiConn *connectors.KalshiConnector, groupLogger *zap.SugaredLogger) {
+			if err := kalshiConn.WatchCredentials(runCtx, groupLogger); err != nil {
+				sugar.Warnf("Kalshi credential watcher stopped for group %q: %v", group.Name, err)
+			}
+		}(group, kalshiConn, groupLogger)
+	}
+
+	// Watch the subscription file for hot-reloads: added/removed/changed
+	// markets are applied to the live connections of the group they belong
+	// to, incrementally and without dropping unrelated feeds.
+	watcher := config.NewWatcher(cfg, sugar)
 	go func() {
-		if err := kalshiConn.Start(); err != nil {
-			sugar.Errorf("Kalshi connector failed: %v", err)
+		if err := watcher.Run(runCtx); err != nil {
+			sugar.Warnf("Config watcher stopped: %v", err)
 		}
 	}()
-
-	// Polymarket
 	go func() {
-		if err := polyConn.Start(); err != nil {
-			sugar.Errorf("Polymarket connector failed: %v", err)
+		for gd := range watcher.Deltas() {
+			gc, ok := byGroup[gd.GroupName]
+			if !ok {
+				sugar.Warnf("Received subscription delta for unknown group %q, ignoring", gd.GroupName)
+				continue
+			}
+			if err := gc.kalshi.ApplySubscriptionDelta(gd.Delta); err != nil {
+				sugar.Errorf("Failed to apply subscription delta to Kalshi connector for group %q: %v", gd.GroupName, err)
+			}
+			if err := gc.poly.ApplySubscriptionDelta(gd.Delta); err != nil {
+				sugar.Errorf("Failed to apply subscription delta to Polymarket connector for group %q: %v", gd.GroupName, err)
+			}
+			if err := gc.manifold.ApplySubscriptionDelta(gd.Delta); err != nil {
+				sugar.Errorf("Failed to apply subscription delta to Manifold connector for group %q: %v", gd.GroupName, err)
+			}
 		}
 	}()
 
-	// Start the Tick Processor (Reads channel -> Writes to Redis)
-	go processTicks(msgChan, redisClient, metricsRegistry, sugar)
-
 	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	
+
 	<-sigChan
 	sugar.Info("Received shutdown signal")
 
@@ -94,21 +171,6 @@ func main() {
 	sugar.Info("Shutdown complete")
 }
 
-// processTicks reads ticks from the channel and publishes them to Redis
-func processTicks(ch <-chan models.Tick, rdb *redis.Client, m *metrics.Registry, logger *zap.SugaredLogger) {
-	for tick := range ch {
-		// Record metrics
-		m.RecordMessage(tick.Source, tick.TimestampSource, true)
-		m.RecordPrice(tick.Source, tick.ContractID, tick.Price)
-
-		// Publish to Redis (Stream + PubSub)
-		if err := rdb.PublishTick(&tick); err != nil {
-			logger.Errorf("Failed to publish tick from %s: %v", tick.Source, err)
-			m.RecordError(tick.Source, "redis_publish_error")
-		}
-	}
-}
-
 func startMetricsServer(port int, logger *zap.SugaredLogger) {
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
@@ -122,4 +184,4 @@ func startMetricsServer(port int, logger *zap.SugaredLogger) {
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		logger.Fatalf("Metrics server failed: %v", err)
 	}
-}
\ No newline at end of file
+}
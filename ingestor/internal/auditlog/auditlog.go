@@ -0,0 +1,136 @@
+// internal/auditlog/auditlog.go
+//
+// Package auditlog writes a rotating JSONL copy of every published tick to
+// disk, independent of Redis, so operators can replay historical ticks for
+// backtesting or arbitrage forensics without relying on Redis persistence.
+package auditlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/dragonuber/echoarb/ingestor/internal/config"
+	"github.com/dragonuber/echoarb/ingestor/internal/models"
+)
+
+// auditWritesTotal counts ticks written to the audit log, by source.
+var auditWritesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "echoarb_audit_writes_total",
+	Help: "Total ticks written to the audit log, by source",
+}, []string{"source"})
+
+const maxRotationSlots = 999
+
+// Logger appends each tick as one JSON line to a file, rotating by size with
+// numeric suffixes (ticks.log, ticks.log.001, ...). A disabled Logger is a
+// no-op so callers don't need to branch on config.AuditLog.Enabled.
+type Logger struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	enabled  bool
+	file     *os.File
+}
+
+// New opens (or creates) the audit log file described by cfg. If cfg.Enabled
+// is false, New returns a Logger whose methods are all no-ops.
+func New(cfg config.AuditLogConfig) (*Logger, error) {
+	if !cfg.Enabled {
+		return &Logger{enabled: false}, nil
+	}
+
+	f, err := os.OpenFile(cfg.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log %s: %w", cfg.Path, err)
+	}
+
+	return &Logger{
+		path:     cfg.Path,
+		maxBytes: cfg.MaxBytes,
+		enabled:  true,
+		file:     f,
+	}, nil
+}
+
+// WriteTick appends tick to the log as one JSON line, rotating first if the
+// write would push the file past MaxBytes.
+func (l *Logger) WriteTick(tick *models.Tick) error {
+	if !l.enabled {
+		return nil
+	}
+
+	data, err := json.Marshal(tick)
+	if err != nil {
+		return fmt.Errorf("marshal tick for audit log: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.rotateIfNeeded(int64(len(data))); err != nil {
+		return err
+	}
+	if _, err := l.file.Write(data); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	auditWritesTotal.WithLabelValues(tick.Source).Inc()
+	return nil
+}
+
+// rotateIfNeeded rotates the log file if appending nextWrite bytes would put
+// it over maxBytes. Caller must hold l.mu.
+func (l *Logger) rotateIfNeeded(nextWrite int64) error {
+	info, err := l.file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat audit log: %w", err)
+	}
+	if info.Size()+nextWrite <= l.maxBytes {
+		return nil
+	}
+	return l.rotate()
+}
+
+// rotate finds the first free .NNN suffix (up to .999), closes the current
+// fd, renames the active file onto that suffix, and reopens a fresh file at
+// the original path. Caller must hold l.mu.
+func (l *Logger) rotate() error {
+	var target string
+	for i := 1; i <= maxRotationSlots; i++ {
+		candidate := fmt.Sprintf("%s.%03d", l.path, i)
+		if _, err := os.Lstat(candidate); os.IsNotExist(err) {
+			target = candidate
+			break
+		}
+	}
+	if target == "" {
+		return fmt.Errorf("audit log rotation: no free .NNN slot for %s", l.path)
+	}
+
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("close audit log before rotation: %w", err)
+	}
+	if err := os.Rename(l.path, target); err != nil {
+		return fmt.Errorf("rotate audit log to %s: %w", target, err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("reopen audit log after rotation: %w", err)
+	}
+	l.file = f
+	return nil
+}
+
+// Close closes the underlying file, if the logger is enabled.
+func (l *Logger) Close() error {
+	if !l.enabled {
+		return nil
+	}
+	return l.file.Close()
+}
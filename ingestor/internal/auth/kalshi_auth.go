@@ -25,8 +25,15 @@ func NewKalshiAuth(keyID, pemPath string) (*KalshiAuth, error) {
 	if err != nil {
 		return nil, fmt.Errorf("read key file: %w", err)
 	}
+	return NewKalshiAuthFromPEM(keyID, keyData)
+}
 
-	block, _ := pem.Decode(keyData)
+// NewKalshiAuthFromPEM builds a KalshiAuth from already-loaded PEM bytes,
+// for callers (config.secrets resolvers) that read the key material from
+// somewhere other than a local file - Vault, AWS Secrets Manager, or an
+// env var - and so never have a path to hand NewKalshiAuth.
+func NewKalshiAuthFromPEM(keyID string, pemData []byte) (*KalshiAuth, error) {
+	block, _ := pem.Decode(pemData)
 	if block == nil {
 		return nil, fmt.Errorf("failed to decode PEM block")
 	}
@@ -2,12 +2,27 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/auth"
+	"github.com/dragonuber/echoarb/ingestor/internal/config/secrets"
+	"github.com/dragonuber/echoarb/ingestor/internal/retry"
 )
 
+// SecretCacheTTL controls how long a resolved Kalshi SecretRef is
+// considered fresh. Callers of WatchKalshiCredentials/WatchGroupCredentials
+// should poll at this same interval (connectors.kalshiCredentialRotationInterval
+// does, via WatchGroupCredentials): a
+// rotated secret reaches the live signer within roughly one TTL window of
+// being rotated in the backend, and polling more often than this just
+// re-reads the cache.
+const SecretCacheTTL = 5 * time.Minute
+
 // Config holds all application configuration
 type Config struct {
 	// Service config
@@ -21,38 +36,142 @@ type Config struct {
 	// API endpoints
 	KalshiWSURL    string `json:"kalshi_ws_url"`
 	PolyWSURL      string `json:"poly_ws_url"`
+	ManifoldAPIURL string `json:"manifold_api_url"`
+
+	// Compression mode for inbound WS frames: "gzip", "deflate", or "none"
+	Compression string `json:"compression"`
+
+	// Kalshi authentication: SecretRefs resolved through a
+	// secrets.Resolver rather than embedded directly in config.
+	// KalshiAPIKey and KalshiPrivateKeyPEM hold the values last resolved
+	// from the refs below - Validate, Preflight and TestKalshiCredentials
+	// read these directly; each Kalshi connector instead resolves its
+	// group's credentials through ResolveGroupCredentials. Secrets holds the
+	// resolver Load built, kept around so the connector can re-resolve on
+	// a rotation poll.
+	KalshiAPIKeyRef     secrets.SecretRef `json:"kalshi_api_key_ref"`
+	KalshiPrivateKeyRef secrets.SecretRef `json:"kalshi_private_key_ref"`
 
-	// Kalshi authentication
-	KalshiAPIKey        string `json:"kalshi_api_key"`
-	KalshiPrivateKeyPEM string `json:"kalshi_private_key_pem"` // Path to PEM file
+	KalshiAPIKey        string `json:"-"`
+	KalshiPrivateKeyPEM []byte `json:"-"`
 
-	// Market subscriptions
+	Secrets *secrets.CachedResolver `json:"-"`
+
+	// Market subscriptions, flattened across every group in Groups - kept
+	// populated even for a grouped config file so existing callers that
+	// only know about a single feed don't need to change.
 	Subscriptions []MarketSubscription `json:"subscriptions"`
 
+	// Groups is every SubscriptionGroup this process fans out to. A legacy
+	// flat "subscriptions" config file is auto-wrapped into a single group
+	// named defaultGroupName by loadSubscriptions.
+	Groups []SubscriptionGroup `json:"-"`
+
 	// Connection settings
 	Reconnect ReconnectConfig `json:"reconnect"`
+
+	// Audit log settings
+	AuditLog AuditLogConfig `json:"audit_log"`
+
+	// Shared-memory ring buffer settings, for zero-copy tick fan-out to
+	// co-located consumers
+	Shm ShmConfig `json:"shm"`
+}
+
+type ShmConfig struct {
+	Enabled   bool   `json:"enabled"`
+	Path      string `json:"path"`
+	RingBytes int    `json:"ring_bytes"`
+}
+
+type AuditLogConfig struct {
+	Path     string `json:"path"`
+	MaxBytes int64  `json:"max_bytes"`
+	Enabled  bool   `json:"enabled"`
 }
 
 type RedisConfig struct {
-	Host         string `json:"host"`
-	Port         int    `json:"port"`
-	Password     string `json:"password"`
-	DB           int    `json:"db"`
-	PoolSize     int    `json:"pool_size"`
-	MinIdleConns int    `json:"min_idle_conns"`
+	// Mode selects the client topology: "single" (default), "cluster",
+	// "sentinel", or "ring". Host/Port are used for "single"; the other
+	// modes read Addrs (and MasterName for "sentinel").
+	Mode string `json:"mode"`
+
+	Host         string   `json:"host"`
+	Port         int      `json:"port"`
+	Addrs        []string `json:"addrs"`       // Cluster/Ring node addresses, "host:port"
+	MasterName   string   `json:"master_name"` // Sentinel master name; Addrs are the sentinel addresses
+	Password     string   `json:"password"`
+	DB           int      `json:"db"`
+	PoolSize     int      `json:"pool_size"`
+	MinIdleConns int      `json:"min_idle_conns"`
+
+	// StreamMaxLen caps the market_ticks stream's count-based retention.
+	// 0 falls back to redis.StreamMaxLen. Use TrimStreamMinID for
+	// time-based retention instead.
+	StreamMaxLen int64 `json:"stream_max_len"`
 }
 
 type ReconnectConfig struct {
 	InitialInterval time.Duration `json:"initial_interval"`
 	MaxInterval     time.Duration `json:"max_interval"`
 	MaxRetries      int           `json:"max_retries"` // 0 = infinite
+
+	// JitterStrategy selects retry.JitterFull/retry.JitterDecorrelated over
+	// the legacy Â±25% additive jitter. Left empty, connectors fall back to
+	// the legacy jitter for compatibility with configs written before this
+	// existed.
+	JitterStrategy retry.JitterStrategy `json:"jitter_strategy"`
+
+	// BreakerThreshold is the number of consecutive failures before the
+	// circuit opens and reconnect attempts are skipped until BreakerTimeout
+	// elapses. 0 disables the breaker.
+	BreakerThreshold int           `json:"breaker_threshold"`
+	BreakerTimeout   time.Duration `json:"breaker_timeout"`
+}
+
+// Breaker returns a new retry.CircuitBreaker configured from r, or nil if
+// r.BreakerThreshold is 0 (the breaker is disabled). Each connector owns the
+// instance it gets back, since a CircuitBreaker tracks its own state across
+// the connector's reconnect attempts.
+func (r ReconnectConfig) Breaker() *retry.CircuitBreaker {
+	if r.BreakerThreshold <= 0 {
+		return nil
+	}
+	return retry.NewCircuitBreaker(r.BreakerThreshold, r.BreakerTimeout)
+}
+
+// defaultGroupName is the SubscriptionGroup a legacy flat "subscriptions"
+// config file is auto-wrapped into, so a single-tenant deployment behaves
+// exactly as it did before SubscriptionGroup existed.
+const defaultGroupName = "default"
+
+// CredentialsRef is a SubscriptionGroup's Kalshi key pair. Either field left
+// with a zero Provider falls back to the top-level KalshiAPIKeyRef /
+// KalshiPrivateKeyRef, so a group only needs to override what differs from
+// the process-wide default - most deployments with one tenant set neither.
+type CredentialsRef struct {
+	KalshiAPIKeyRef     secrets.SecretRef `json:"kalshi_api_key_ref"`
+	KalshiPrivateKeyRef secrets.SecretRef `json:"kalshi_private_key_ref"`
+}
+
+// SubscriptionGroup is one independent tenant's worth of market feeds: its
+// own Kalshi/Polymarket credentials, its own Redis key namespace, and its
+// own subscription list. A process fans out one Kalshi and one Polymarket
+// connector per group, so several tenants sharing this ingestor never
+// cross-publish or cross-subscribe.
+type SubscriptionGroup struct {
+	Name          string               `json:"name"`
+	Credentials   CredentialsRef       `json:"credentials"`
+	RedisPrefix   string               `json:"redis_prefix"`
+	Subscriptions []MarketSubscription `json:"subscriptions"`
 }
 
 type MarketSubscription struct {
-	ID          string           `json:"id"`
-	Description string           `json:"description"`
-	Kalshi      *KalshiMarket    `json:"kalshi,omitempty"`
-	Polymarket  *PolymarketMarket `json:"polymarket,omitempty"`
+	ID          string             `json:"id"`
+	Description string             `json:"description"`
+	Kalshi      *KalshiMarket      `json:"kalshi,omitempty"`
+	Polymarket  *PolymarketMarket  `json:"polymarket,omitempty"`
+	Manifold    *ManifoldMarketRef `json:"manifold,omitempty"`
 }
 
 type KalshiMarket struct {
@@ -63,6 +182,13 @@ type PolymarketMarket struct {
 	TokenID string `json:"token_id"`
 }
 
+// ManifoldMarketRef identifies a Manifold market by its URL slug - Manifold
+// has no per-market WS feed, so ManifoldConnector polls the /markets API
+// for each slug configured across the process's groups instead.
+type ManifoldMarketRef struct {
+	Slug string `json:"slug"`
+}
+
 // Load reads configuration from file and environment variables
 func Load() (*Config, error) {
 	// Default configuration
@@ -71,24 +197,51 @@ func Load() (*Config, error) {
 		MetricsPort: getEnvInt("METRICS_PORT", 9090),
 		LogLevel:    getEnv("LOG_LEVEL", "info"),
 		Redis: RedisConfig{
+			Mode:         getEnv("REDIS_MODE", "single"),
 			Host:         getEnv("REDIS_HOST", "localhost"),
 			Port:         getEnvInt("REDIS_PORT", 6379),
+			Addrs:        getEnvList("REDIS_ADDRS", nil),
+			MasterName:   getEnv("REDIS_MASTER_NAME", ""),
 			Password:     getEnv("REDIS_PASSWORD", ""),
 			DB:           getEnvInt("REDIS_DB", 0),
 			PoolSize:     getEnvInt("REDIS_POOL_SIZE", 10),
 			MinIdleConns: getEnvInt("REDIS_MIN_IDLE_CONNS", 5),
+			StreamMaxLen: int64(getEnvInt("REDIS_STREAM_MAX_LEN", 0)),
 		},
 		KalshiWSURL:    getEnv("KALSHI_WS_URL", "wss://api.elections.kalshi.com/trade-api/ws/v2"),
 		PolyWSURL:      getEnv("POLY_WS_URL", "wss://ws-subscriptions-clob.polymarket.com/ws"),
-		
-		// Kalshi auth
-		KalshiAPIKey:        getEnv("KALSHI_API_KEY", ""),
-		KalshiPrivateKeyPEM: getEnv("KALSHI_PRIVATE_KEY_PATH", "./keys/kalshi_private_key.pem"),
-		
+		ManifoldAPIURL: getEnv("MANIFOLD_API_URL", "https://api.manifold.markets/v0/markets"),
+		Compression:    getEnv("COMPRESSION", "none"),
+
+		// Kalshi auth: defaults preserve the original env/file behavior -
+		// the API key from KALSHI_API_KEY, the private key from the file
+		// at KALSHI_PRIVATE_KEY_PATH - while letting either be pointed at
+		// Vault or AWS Secrets Manager instead via the *_SECRET_PROVIDER
+		// env vars.
+		KalshiAPIKeyRef:     secretRefFromEnv("KALSHI_API_KEY", secrets.ProviderEnv, "KALSHI_API_KEY", ""),
+		KalshiPrivateKeyRef: secretRefFromEnv("KALSHI_PRIVATE_KEY", secrets.ProviderFile, getEnv("KALSHI_PRIVATE_KEY_PATH", "./keys/kalshi_private_key.pem"), ""),
+
+		Secrets: secrets.NewCachedResolver(secrets.DefaultRegistry(context.Background()), SecretCacheTTL),
+
 		Reconnect: ReconnectConfig{
-			InitialInterval: 5 * time.Second,
-			MaxInterval:     5 * time.Minute,
-			MaxRetries:      0, // Infinite retries
+			InitialInterval:  5 * time.Second,
+			MaxInterval:      5 * time.Minute,
+			MaxRetries:       0, // Infinite retries
+			JitterStrategy:   retry.JitterDecorrelated,
+			BreakerThreshold: 5,
+			BreakerTimeout:   30 * time.Second,
+		},
+
+		AuditLog: AuditLogConfig{
+			Path:     getEnv("AUDIT_LOG_PATH", "./logs/ticks.log"),
+			MaxBytes: int64(getEnvInt("AUDIT_LOG_MAX_BYTES", 100*1024*1024)),
+			Enabled:  getEnv("AUDIT_LOG_ENABLED", "false") == "true",
+		},
+
+		Shm: ShmConfig{
+			Enabled:   getEnv("SHM_ENABLED", "false") == "true",
+			Path:      getEnv("SHM_RING_PATH", "/dev/shm/echoarb_ticks.ring"),
+			RingBytes: getEnvInt("SHM_RING_BYTES", 64*1024*1024),
 		},
 	}
 
@@ -98,6 +251,10 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to load market subscriptions: %w", err)
 	}
 
+	if err := cfg.resolveKalshiCredentials(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to resolve kalshi credentials: %w", err)
+	}
+
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -106,48 +263,253 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// resolveKalshiCredentials resolves KalshiAPIKeyRef and KalshiPrivateKeyRef
+// through cfg.Secrets and stores the results in KalshiAPIKey and
+// KalshiPrivateKeyPEM. Called once by Load at startup, and again by
+// WatchKalshiCredentials each time it polls for a rotated value.
+func (c *Config) resolveKalshiCredentials(ctx context.Context) error {
+	apiKey, pem, err := c.resolveCredentialsRef(ctx, CredentialsRef{})
+	if err != nil {
+		return err
+	}
+	c.KalshiAPIKey = apiKey
+	c.KalshiPrivateKeyPEM = pem
+	return nil
+}
+
+// resolveCredentialsRef resolves a SubscriptionGroup's CredentialsRef
+// through c.Secrets, falling back field-by-field to the top-level
+// KalshiAPIKeyRef/KalshiPrivateKeyRef wherever ref leaves a Provider unset -
+// the zero value, so a group that doesn't override credentials resolves to
+// exactly what the process-wide config already would.
+func (c *Config) resolveCredentialsRef(ctx context.Context, ref CredentialsRef) (apiKey string, privateKeyPEM []byte, err error) {
+	apiKeyRef := ref.KalshiAPIKeyRef
+	if apiKeyRef.Provider == "" {
+		apiKeyRef = c.KalshiAPIKeyRef
+	}
+	privateKeyRef := ref.KalshiPrivateKeyRef
+	if privateKeyRef.Provider == "" {
+		privateKeyRef = c.KalshiPrivateKeyRef
+	}
+
+	apiKey, err = c.Secrets.Resolve(ctx, apiKeyRef)
+	if err != nil {
+		return "", nil, fmt.Errorf("resolve kalshi api key (%s): %w", apiKeyRef, err)
+	}
+	pemStr, err := c.Secrets.Resolve(ctx, privateKeyRef)
+	if err != nil {
+		return "", nil, fmt.Errorf("resolve kalshi private key (%s): %w", privateKeyRef, err)
+	}
+	return apiKey, []byte(pemStr), nil
+}
+
+// ResolveGroupCredentials resolves a SubscriptionGroup's Kalshi key pair,
+// falling back to the top-level credentials for anything the group doesn't
+// override. It's what each group's KalshiConnector calls at construction
+// time instead of reading c.KalshiAPIKey/KalshiPrivateKeyPEM directly.
+func (c *Config) ResolveGroupCredentials(ctx context.Context, g SubscriptionGroup) (apiKey string, privateKeyPEM []byte, err error) {
+	return c.resolveCredentialsRef(ctx, g.Credentials)
+}
+
+// WatchGroupCredentials polls a SubscriptionGroup's Kalshi SecretRefs every
+// interval, the same way WatchKalshiCredentials does for the top-level
+// credentials, invoking onRotate with the newly-resolved values whenever
+// they differ from lastAPIKey/lastPrivateKeyPEM. It blocks until ctx is
+// canceled.
+func (c *Config) WatchGroupCredentials(ctx context.Context, g SubscriptionGroup, interval time.Duration, onRotate func(apiKey string, privateKeyPEM []byte), onError func(error)) error {
+	lastAPIKey, lastPrivateKeyPEM, err := c.ResolveGroupCredentials(ctx, g)
+	if err != nil {
+		return fmt.Errorf("resolve initial credentials for group %q: %w", g.Name, err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			apiKey, privateKeyPEM, err := c.ResolveGroupCredentials(ctx, g)
+			if err != nil {
+				onError(fmt.Errorf("resolve credentials for group %q: %w", g.Name, err))
+				continue
+			}
+			if apiKey == lastAPIKey && string(privateKeyPEM) == string(lastPrivateKeyPEM) {
+				continue
+			}
+			lastAPIKey, lastPrivateKeyPEM = apiKey, privateKeyPEM
+			onRotate(apiKey, privateKeyPEM)
+		}
+	}
+}
+
+// WatchKalshiCredentials polls the configured Kalshi SecretRefs every
+// interval and invokes onRotate with the newly-resolved values whenever
+// either one differs from what's currently loaded, so a key rotated in
+// Vault/AWS Secrets Manager (or a rewritten credentials file) reaches the
+// live signer without restarting the process. It blocks until ctx is
+// canceled. A resolve that fails is reported via onError and leaves the
+// last-good credentials in place; since cfg.Secrets caches each ref for
+// SecretCacheTTL, polling more often than that costs nothing extra.
+func (c *Config) WatchKalshiCredentials(ctx context.Context, interval time.Duration, onRotate func(apiKey string, privateKeyPEM []byte), onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			apiKey, err := c.Secrets.Resolve(ctx, c.KalshiAPIKeyRef)
+			if err != nil {
+				onError(fmt.Errorf("resolve kalshi api key: %w", err))
+				continue
+			}
+			pemStr, err := c.Secrets.Resolve(ctx, c.KalshiPrivateKeyRef)
+			if err != nil {
+				onError(fmt.Errorf("resolve kalshi private key: %w", err))
+				continue
+			}
+
+			if apiKey == c.KalshiAPIKey && pemStr == string(c.KalshiPrivateKeyPEM) {
+				continue
+			}
+
+			c.KalshiAPIKey = apiKey
+			c.KalshiPrivateKeyPEM = []byte(pemStr)
+			onRotate(apiKey, c.KalshiPrivateKeyPEM)
+		}
+	}
+}
+
+// secretRefFromEnv builds a SecretRef for a credential, reading its
+// provider/path/field from <prefix>_SECRET_PROVIDER, <prefix>_SECRET_PATH
+// and <prefix>_SECRET_FIELD, falling back to defaultProvider/defaultPath so
+// existing env/file-based deployments keep working unchanged.
+func secretRefFromEnv(prefix string, defaultProvider secrets.Provider, defaultPath, defaultField string) secrets.SecretRef {
+	return secrets.SecretRef{
+		Provider: secrets.Provider(getEnv(prefix+"_SECRET_PROVIDER", string(defaultProvider))),
+		Path:     getEnv(prefix+"_SECRET_PATH", defaultPath),
+		Field:    getEnv(prefix+"_SECRET_FIELD", defaultField),
+	}
+}
+
 func loadSubscriptions(path string, cfg *Config) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		// Config file is optional in development
 		if os.IsNotExist(err) && cfg.Environment == "development" {
+			cfg.Groups = []SubscriptionGroup{{Name: defaultGroupName}}
 			cfg.Subscriptions = []MarketSubscription{} // Empty subscriptions for testing
 			return nil
 		}
 		return err
 	}
 
-	var subscriptionsConfig struct {
-		Subscriptions []MarketSubscription `json:"subscriptions"`
-	}
-
-	if err := json.Unmarshal(data, &subscriptionsConfig); err != nil {
+	groups, err := parseSubscriptionGroups(data)
+	if err != nil {
 		return err
 	}
 
-	cfg.Subscriptions = subscriptionsConfig.Subscriptions
+	cfg.Groups = groups
+	cfg.Subscriptions = flattenGroupSubscriptions(groups)
 	return nil
 }
 
+// parseSubscriptionGroups reads a CONFIG_PATH file shaped either as the
+// legacy flat `{"subscriptions": [...]}` or the grouped
+// `{"groups": [{"name": ..., "subscriptions": [...]}, ...]}`. A legacy flat
+// file is wrapped into a single group named defaultGroupName, so Load and
+// Watcher's hot-reload share one parsing path regardless of which shape a
+// given deployment uses.
+func parseSubscriptionGroups(data []byte) ([]SubscriptionGroup, error) {
+	var parsed struct {
+		Subscriptions []MarketSubscription `json:"subscriptions"`
+		Groups        []SubscriptionGroup  `json:"groups"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+	if len(parsed.Groups) > 0 {
+		return parsed.Groups, nil
+	}
+	return []SubscriptionGroup{{Name: defaultGroupName, Subscriptions: parsed.Subscriptions}}, nil
+}
+
+// flattenGroupSubscriptions concatenates every group's subscriptions into
+// one list, for callers (Config.Subscriptions) that don't need to know
+// which group a subscription belongs to.
+func flattenGroupSubscriptions(groups []SubscriptionGroup) []MarketSubscription {
+	var all []MarketSubscription
+	for _, g := range groups {
+		all = append(all, g.Subscriptions...)
+	}
+	return all
+}
+
 // Validate checks if configuration is valid
 func (c *Config) Validate() error {
-	if c.Redis.Host == "" {
-		return fmt.Errorf("redis host is required")
+	switch c.Redis.Mode {
+	case "", "single":
+		if c.Redis.Host == "" {
+			return fmt.Errorf("redis host is required")
+		}
+	case "cluster", "sentinel", "ring":
+		if len(c.Redis.Addrs) == 0 {
+			return fmt.Errorf("redis addrs are required for mode %q", c.Redis.Mode)
+		}
+		if c.Redis.Mode == "sentinel" && c.Redis.MasterName == "" {
+			return fmt.Errorf("redis master_name is required for sentinel mode")
+		}
+	default:
+		return fmt.Errorf("unknown redis mode %q", c.Redis.Mode)
 	}
-	
+
+	// Validate() verifies each Kalshi SecretRef actually resolved to a
+	// usable key pair at startup - the same "test credentials before use"
+	// idea TestKalshiCredentials applies against the live Kalshi API,
+	// just checked locally against the decoded key instead of over the
+	// network.
 	if c.KalshiAPIKey == "" {
-		return fmt.Errorf("KALSHI_API_KEY environment variable is required")
+		return fmt.Errorf("kalshi api key secret (%s) resolved to an empty value", c.KalshiAPIKeyRef)
+	}
+	if len(c.KalshiPrivateKeyPEM) == 0 {
+		return fmt.Errorf("kalshi private key secret (%s) resolved to an empty value", c.KalshiPrivateKeyRef)
+	}
+	if _, err := auth.NewKalshiAuthFromPEM(c.KalshiAPIKey, c.KalshiPrivateKeyPEM); err != nil {
+		return fmt.Errorf("kalshi private key secret (%s) does not decode to a usable key: %w", c.KalshiPrivateKeyRef, err)
 	}
 
-	if c.KalshiPrivateKeyPEM == "" {
-		return fmt.Errorf("KALSHI_PRIVATE_KEY_PATH environment variable is required")
+	if err := c.validateGroups(); err != nil {
+		return err
 	}
 
-	// Check if private key file exists
-	if _, err := os.Stat(c.KalshiPrivateKeyPEM); os.IsNotExist(err) {
-		return fmt.Errorf("Kalshi private key file not found: %s", c.KalshiPrivateKeyPEM)
+	return nil
+}
+
+// validateGroups checks that every SubscriptionGroup has a name, names are
+// unique, and each group's own subscription list passes the same checks
+// Watcher's hot-reload applies to a reloaded one.
+func (c *Config) validateGroups() error {
+	if len(c.Groups) == 0 {
+		return fmt.Errorf("at least one subscription group is required")
 	}
 
+	seen := make(map[string]bool, len(c.Groups))
+	for _, g := range c.Groups {
+		if g.Name == "" {
+			return fmt.Errorf("subscription group missing name")
+		}
+		if seen[g.Name] {
+			return fmt.Errorf("duplicate subscription group name %q", g.Name)
+		}
+		seen[g.Name] = true
+
+		if err := validateSubscriptions(g.Subscriptions); err != nil {
+			return fmt.Errorf("group %q: %w", g.Name, err)
+		}
+	}
 	return nil
 }
 
@@ -168,3 +530,20 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvList reads a comma-separated list, e.g. REDIS_ADDRS=10.0.0.1:6379,10.0.0.2:6379
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parts := strings.Split(value, ",")
+	result := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
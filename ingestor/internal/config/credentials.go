@@ -0,0 +1,99 @@
+// internal/config/credentials.go
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/auth"
+)
+
+// CredentialErrorKind distinguishes why a credentials check failed, so
+// callers (e.g. a deploy pipeline) can react differently to a bad key pair
+// versus a transient network problem.
+type CredentialErrorKind string
+
+const (
+	// KeyDecodeError means the resolved private key (cfg.KalshiPrivateKeyPEM)
+	// doesn't decode to a usable RSA key, or signing with it failed.
+	KeyDecodeError CredentialErrorKind = "key_decode_error"
+	// NetworkError means the request to Kalshi couldn't be completed at all,
+	// or Kalshi returned something other than a clean accept/reject.
+	NetworkError CredentialErrorKind = "network_error"
+	// InvalidCredentials means Kalshi reached the request and rejected the
+	// signed challenge - the API key or key pair is wrong.
+	InvalidCredentials CredentialErrorKind = "invalid_credentials"
+)
+
+// CredentialError is returned by TestKalshiCredentials, typed by Kind so
+// callers can map it to a distinct exit code.
+type CredentialError struct {
+	Kind CredentialErrorKind
+	Err  error
+}
+
+func (e *CredentialError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Kind, e.Err)
+}
+
+func (e *CredentialError) Unwrap() error {
+	return e.Err
+}
+
+// kalshiCredentialCheckPath is a lightweight authenticated endpoint: it
+// requires valid signed headers but returns quickly and has no side effects.
+const kalshiCredentialCheckPath = "/trade-api/v2/portfolio/balance"
+
+// TestKalshiCredentials signs a timestamped challenge for
+// kalshiCredentialCheckPath with the configured API key and private key,
+// exactly as the live Kalshi connector's signer does, and issues it against
+// Kalshi's REST API to prove the key pair is accepted end-to-end. It returns
+// a *CredentialError distinguishing why it failed: the key itself doesn't
+// decode, the request never reached Kalshi, or Kalshi rejected it.
+func TestKalshiCredentials(cfg *Config) error {
+	kalshiAuth, err := auth.NewKalshiAuthFromPEM(cfg.KalshiAPIKey, cfg.KalshiPrivateKeyPEM)
+	if err != nil {
+		return &CredentialError{Kind: KeyDecodeError, Err: err}
+	}
+
+	headers, err := kalshiAuth.GenerateHeaders(http.MethodGet, kalshiCredentialCheckPath)
+	if err != nil {
+		return &CredentialError{Kind: KeyDecodeError, Err: fmt.Errorf("sign challenge: %w", err)}
+	}
+
+	url := kalshiRESTBaseURL(cfg.KalshiWSURL) + kalshiCredentialCheckPath
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return &CredentialError{Kind: NetworkError, Err: fmt.Errorf("build request: %w", err)}
+	}
+	req.Header = headers
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return &CredentialError{Kind: NetworkError, Err: err}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return &CredentialError{Kind: InvalidCredentials, Err: fmt.Errorf("kalshi rejected the signed request: %s", resp.Status)}
+	default:
+		return &CredentialError{Kind: NetworkError, Err: fmt.Errorf("unexpected response from kalshi: %s", resp.Status)}
+	}
+}
+
+// kalshiRESTBaseURL derives the REST API origin ("https://host") from the
+// configured websocket URL ("wss://host/trade-api/ws/v2"), so credential
+// checks hit the same environment (prod vs. demo) the connector is
+// configured for without a second URL to keep in sync. The caller appends
+// kalshiCredentialCheckPath, which already carries the /trade-api prefix.
+func kalshiRESTBaseURL(wsURL string) string {
+	url := strings.Replace(wsURL, "wss://", "https://", 1)
+	url = strings.Replace(url, "ws://", "http://", 1)
+	return strings.TrimSuffix(url, "/trade-api/ws/v2")
+}
@@ -0,0 +1,212 @@
+// internal/config/preflight.go
+package config
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/auth"
+	"github.com/dragonuber/echoarb/ingestor/internal/retry"
+	"github.com/gorilla/websocket"
+)
+
+// PreflightStatus is the outcome of a single component check.
+type PreflightStatus string
+
+const (
+	PreflightOK     PreflightStatus = "ok"
+	PreflightFailed PreflightStatus = "failed"
+)
+
+// preflightMaxAttempts bounds every retrying check, so a genuinely-down
+// dependency fails Preflight instead of retrying forever.
+const preflightMaxAttempts = 100
+
+// PreflightCheck is one component's result: whether it came up, how long the
+// successful attempt took (or how long was spent retrying before giving up),
+// and the error from the last failed attempt if it never succeeded.
+type PreflightCheck struct {
+	Component string
+	Status    PreflightStatus
+	Latency   time.Duration
+	Attempts  int
+	Err       error
+}
+
+// PreflightReport is the combined result of Config.Preflight, so operators
+// see one actionable failure summary instead of watching goroutines
+// crash-loop against a dependency that was never going to come up.
+type PreflightReport struct {
+	Checks []PreflightCheck
+}
+
+// OK reports whether every component check succeeded.
+func (r *PreflightReport) OK() bool {
+	for _, c := range r.Checks {
+		if c.Status != PreflightOK {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the report as a human-readable summary, one line per
+// component.
+func (r *PreflightReport) String() string {
+	var b strings.Builder
+	for _, c := range r.Checks {
+		if c.Status == PreflightOK {
+			fmt.Fprintf(&b, "[ok] %s (%v, %d attempt(s))\n", c.Component, c.Latency, c.Attempts)
+		} else {
+			fmt.Fprintf(&b, "[FAILED] %s after %d attempt(s): %v\n", c.Component, c.Attempts, c.Err)
+		}
+	}
+	return b.String()
+}
+
+// Preflight actively verifies every external dependency the ingestor needs
+// before it starts its connectors: Redis reachability, the Kalshi and
+// Polymarket endpoints, and that the configured Kalshi private key decodes
+// to a usable RSA key. Each check retries with c.Reconnect's backoff,
+// capped at preflightMaxAttempts, so a dependency that's merely slow to
+// come up (e.g. Redis still starting in the same compose stack) doesn't
+// fail the whole process on the first attempt - but a dependency that's
+// genuinely down still fails fast instead of retrying forever.
+func (c *Config) Preflight(ctx context.Context) *PreflightReport {
+	return &PreflightReport{
+		Checks: []PreflightCheck{
+			c.runCheck(ctx, "redis", c.checkRedis),
+			c.runCheck(ctx, "kalshi_ws", func() error { return checkWSReachable(c.KalshiWSURL) }),
+			c.runCheck(ctx, "polymarket_ws", func() error { return checkWSReachable(c.PolyWSURL) }),
+			c.runCheck(ctx, "kalshi_private_key", c.checkKalshiPrivateKey),
+		},
+	}
+}
+
+// runCheck retries fn with exponential backoff (from c.Reconnect, capped at
+// preflightMaxAttempts) and wraps the outcome into a PreflightCheck.
+func (c *Config) runCheck(ctx context.Context, component string, fn func() error) PreflightCheck {
+	backoffCfg := retry.Config{
+		InitialInterval: c.Reconnect.InitialInterval,
+		MaxInterval:     c.Reconnect.MaxInterval,
+		Multiplier:      2.0,
+	}
+	if backoffCfg.InitialInterval <= 0 {
+		backoffCfg.InitialInterval = time.Second
+	}
+	if backoffCfg.MaxInterval <= 0 {
+		backoffCfg.MaxInterval = 30 * time.Second
+	}
+	backoff := retry.NewBackoff(backoffCfg)
+
+	start := time.Now()
+	var lastErr error
+	attempts := 0
+	for attempt := 0; attempt < preflightMaxAttempts; attempt++ {
+		if ctx.Err() != nil {
+			lastErr = ctx.Err()
+			break
+		}
+
+		attempts++
+		if lastErr = fn(); lastErr == nil {
+			return PreflightCheck{
+				Component: component,
+				Status:    PreflightOK,
+				Latency:   time.Since(start),
+				Attempts:  attempts,
+			}
+		}
+
+		select {
+		case <-time.After(backoff.Next(attempt)):
+		case <-ctx.Done():
+			lastErr = ctx.Err()
+		}
+	}
+
+	return PreflightCheck{
+		Component: component,
+		Status:    PreflightFailed,
+		Latency:   time.Since(start),
+		Attempts:  attempts,
+		Err:       lastErr,
+	}
+}
+
+// checkRedis dials Redis directly with a raw PING, rather than going through
+// internal/redis (which imports this package, so using it here would be a
+// cycle). Single mode dials Host:Port; cluster/sentinel/ring modes dial the
+// first configured address, which is enough to confirm the network path and
+// the server are up even though it doesn't guarantee every node in the
+// topology is reachable. No AUTH is sent, so a wrong password is not caught
+// here: an error reply to the PING still passes.
+func (c *Config) checkRedis() error {
+	addr := fmt.Sprintf("%s:%d", c.Redis.Host, c.Redis.Port)
+	if len(c.Redis.Addrs) > 0 {
+		addr = c.Redis.Addrs[0]
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, 3*time.Second)
+	if err != nil {
+		return fmt.Errorf("dial redis at %s: %w", addr, err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(3 * time.Second))
+	if _, err := conn.Write([]byte("PING\r\n")); err != nil {
+		return fmt.Errorf("send PING to redis: %w", err)
+	}
+
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return fmt.Errorf("read PING response from redis: %w", err)
+	}
+	// A Sentinel/Cluster node may reply with its own protocol error instead
+	// of PONG for a bare PING, but either way a reply means the TCP path and
+	// the server process are both up - which is all this check promises.
+	if n == 0 {
+		return fmt.Errorf("empty PING response from redis")
+	}
+	return nil
+}
+
+// checkWSReachable performs a real WebSocket handshake against wsURL to
+// confirm the endpoint is reachable before the connector's own dialer
+// depends on it, which would otherwise be the first thing to notice a DNS
+// or network problem. A completed handshake (101 Switching Protocols) is
+// obviously reachable; a bad handshake (e.g. a venue that rejects an
+// unauthenticated dial with a 4xx) still means the TCP/TLS connection and
+// HTTP round trip both succeeded, so that counts as reachable too - we
+// only fail this check when the dial itself never got a response.
+func checkWSReachable(wsURL string) error {
+	dialer := websocket.Dialer{HandshakeTimeout: 5 * time.Second}
+
+	conn, resp, err := dialer.Dial(wsURL, nil)
+	if conn != nil {
+		conn.Close()
+	}
+	if err == nil {
+		return nil
+	}
+	if resp != nil {
+		resp.Body.Close()
+		return nil
+	}
+	return fmt.Errorf("reach %s: %w", wsURL, err)
+}
+
+// checkKalshiPrivateKey runs the already-resolved PEM bytes through the
+// same parsing auth.NewKalshiAuthFromPEM does, so a malformed key is
+// caught here instead of surfacing as an auth failure deep inside the
+// Kalshi connector's own retry loop. The SecretRef itself was already
+// resolved by Config.Load (or rejected there); this only re-checks that it
+// decodes.
+func (c *Config) checkKalshiPrivateKey() error {
+	_, err := auth.NewKalshiAuthFromPEM(c.KalshiAPIKey, c.KalshiPrivateKeyPEM)
+	return err
+}
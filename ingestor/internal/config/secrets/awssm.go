@@ -0,0 +1,57 @@
+// internal/config/secrets/awssm.go
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// secretsManagerAPI is the subset of the AWS SDK's Secrets Manager client
+// this resolver depends on, so tests can substitute a fake instead of a
+// real AWS client.
+type secretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// AWSSecretsManagerResolver resolves a SecretRef against AWS Secrets
+// Manager: Path is the secret ID or ARN. If the secret's SecretString is a
+// flat JSON object, Field selects one key out of it; if Field is empty,
+// the whole SecretString is returned as-is (a plain-text secret).
+type AWSSecretsManagerResolver struct {
+	client secretsManagerAPI
+}
+
+// NewAWSSecretsManagerResolver wraps an already-configured Secrets Manager
+// client (built from the AWS SDK's default credential chain by the caller).
+func NewAWSSecretsManagerResolver(client secretsManagerAPI) *AWSSecretsManagerResolver {
+	return &AWSSecretsManagerResolver{client: client}
+}
+
+func (a *AWSSecretsManagerResolver) Resolve(ctx context.Context, ref SecretRef) (string, error) {
+	out, err := a.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(ref.Path),
+	})
+	if err != nil {
+		return "", fmt.Errorf("get secret %s: %w", ref.Path, err)
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("secret %s has no SecretString", ref.Path)
+	}
+	if ref.Field == "" {
+		return *out.SecretString, nil
+	}
+
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(*out.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object of string fields: %w", ref.Path, err)
+	}
+	value, ok := fields[ref.Field]
+	if !ok {
+		return "", fmt.Errorf("field %q not found in secret %s", ref.Field, ref.Path)
+	}
+	return value, nil
+}
@@ -0,0 +1,66 @@
+// internal/config/secrets/cache.go
+package secrets
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CachedResolver wraps a Resolver with a per-ref TTL cache, so a caller
+// that resolves the same ref repeatedly (Config.Validate at startup, a
+// rotation watcher polling afterwards) doesn't hit Vault or AWS on every
+// call, while still picking up a rotated value within one TTL window.
+type CachedResolver struct {
+	resolver Resolver
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[SecretRef]cacheEntry
+}
+
+type cacheEntry struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// NewCachedResolver wraps resolver with a cache that treats a resolved
+// value as fresh for ttl. A ttl of 0 disables caching - every Resolve call
+// hits the backend.
+func NewCachedResolver(resolver Resolver, ttl time.Duration) *CachedResolver {
+	return &CachedResolver{
+		resolver: resolver,
+		ttl:      ttl,
+		entries:  make(map[SecretRef]cacheEntry),
+	}
+}
+
+func (c *CachedResolver) Resolve(ctx context.Context, ref SecretRef) (string, error) {
+	if c.ttl > 0 {
+		c.mu.Lock()
+		entry, ok := c.entries[ref]
+		c.mu.Unlock()
+		if ok && time.Since(entry.fetchedAt) < c.ttl {
+			return entry.value, nil
+		}
+	}
+
+	value, err := c.resolver.Resolve(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.entries[ref] = cacheEntry{value: value, fetchedAt: time.Now()}
+	c.mu.Unlock()
+
+	return value, nil
+}
+
+// Invalidate drops ref's cached entry, forcing the next Resolve to hit the
+// backend regardless of ttl.
+func (c *CachedResolver) Invalidate(ref SecretRef) {
+	c.mu.Lock()
+	delete(c.entries, ref)
+	c.mu.Unlock()
+}
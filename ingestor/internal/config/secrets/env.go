@@ -0,0 +1,23 @@
+// internal/config/secrets/env.go
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// EnvResolver resolves a SecretRef by reading the environment variable
+// named by ref.Path. It's the built-in provider that preserves the
+// ingestor's original KALSHI_API_KEY-style behavior, now reached through
+// the same Resolver interface as the networked backends. Field is unused -
+// an environment variable holds one value.
+type EnvResolver struct{}
+
+func (EnvResolver) Resolve(_ context.Context, ref SecretRef) (string, error) {
+	value, ok := os.LookupEnv(ref.Path)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", ref.Path)
+	}
+	return value, nil
+}
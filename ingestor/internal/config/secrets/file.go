@@ -0,0 +1,22 @@
+// internal/config/secrets/file.go
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FileResolver resolves a SecretRef by reading the file at ref.Path,
+// trimming any trailing newline characters. Field is unused - one file holds one secret, whether that's
+// an API key or PEM-encoded key material.
+type FileResolver struct{}
+
+func (FileResolver) Resolve(_ context.Context, ref SecretRef) (string, error) {
+	data, err := os.ReadFile(ref.Path)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", ref.Path, err)
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
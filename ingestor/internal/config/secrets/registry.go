@@ -0,0 +1,42 @@
+// internal/config/secrets/registry.go
+package secrets
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// DefaultRegistry builds a Registry with every built-in provider wired up
+// from the process environment: env and file are always available; Vault
+// is registered if VAULT_ADDR is set, using VAULT_TOKEN for static-token
+// auth if present or VAULT_ROLE_ID/VAULT_SECRET_ID (AppRole) otherwise; AWS
+// Secrets Manager is registered using the SDK's default credential chain
+// (env vars, shared config, instance/task role). A deployment that only
+// ever uses "env"/"file" SecretRefs is unaffected by a missing or broken
+// AWS config - DefaultRegistry simply leaves that provider unregistered,
+// and Registry.Resolve reports a clear error only if something actually
+// tries to use it.
+func DefaultRegistry(ctx context.Context) *Registry {
+	r := NewRegistry()
+	r.Register(ProviderEnv, EnvResolver{})
+	r.Register(ProviderFile, FileResolver{})
+
+	if addr := os.Getenv("VAULT_ADDR"); addr != "" {
+		r.Register(ProviderVault, NewVaultResolver(
+			addr,
+			os.Getenv("VAULT_NAMESPACE"),
+			os.Getenv("VAULT_TOKEN"),
+			os.Getenv("VAULT_ROLE_ID"),
+			os.Getenv("VAULT_SECRET_ID"),
+		))
+	}
+
+	if awsCfg, err := config.LoadDefaultConfig(ctx); err == nil {
+		r.Register(ProviderAWSSM, NewAWSSecretsManagerResolver(secretsmanager.NewFromConfig(awsCfg)))
+	}
+
+	return r
+}
@@ -0,0 +1,75 @@
+// internal/config/secrets/secrets.go
+package secrets
+
+import (
+	"context"
+	"fmt"
+)
+
+// Provider identifies which backend resolves a SecretRef.
+type Provider string
+
+const (
+	ProviderEnv   Provider = "env"
+	ProviderFile  Provider = "file"
+	ProviderVault Provider = "vault"
+	ProviderAWSSM Provider = "awssm"
+)
+
+// SecretRef points at one secret value in a pluggable backend, instead of
+// embedding the value (or a bare file path) directly in config. Field
+// selects one named value out of Path for backends that store several at
+// once (Vault's KV v2 versions, an AWS Secrets Manager JSON blob); it's
+// ignored by the env and file providers, which resolve the whole value at
+// Path.
+type SecretRef struct {
+	Provider Provider `json:"provider"`
+	Path     string   `json:"path"`
+	Field    string   `json:"field,omitempty"`
+}
+
+// String renders a SecretRef for logging, e.g. in an error wrapping a
+// failed resolve - never the resolved value itself.
+func (r SecretRef) String() string {
+	if r.Field != "" {
+		return fmt.Sprintf("%s:%s#%s", r.Provider, r.Path, r.Field)
+	}
+	return fmt.Sprintf("%s:%s", r.Provider, r.Path)
+}
+
+// Resolver resolves a SecretRef to its current value. Implementations for
+// Vault and AWS Secrets Manager are expected to be called repeatedly (a
+// rotation watcher polls them), not just once at startup, so they should
+// make a fresh backend call each time rather than caching internally -
+// CachedResolver is the caching layer callers compose them with.
+type Resolver interface {
+	Resolve(ctx context.Context, ref SecretRef) (string, error)
+}
+
+// Registry dispatches a SecretRef to the Resolver registered for its
+// Provider, so config code depends on one interface instead of switching
+// on Provider itself.
+type Registry struct {
+	resolvers map[Provider]Resolver
+}
+
+// NewRegistry creates an empty Registry. Use DefaultRegistry to get one
+// pre-populated with the built-in providers.
+func NewRegistry() *Registry {
+	return &Registry{resolvers: make(map[Provider]Resolver)}
+}
+
+// Register wires resolver up to handle every SecretRef with the given
+// Provider, replacing any resolver previously registered for it.
+func (r *Registry) Register(p Provider, resolver Resolver) {
+	r.resolvers[p] = resolver
+}
+
+// Resolve looks up the Resolver for ref.Provider and delegates to it.
+func (r *Registry) Resolve(ctx context.Context, ref SecretRef) (string, error) {
+	resolver, ok := r.resolvers[ref.Provider]
+	if !ok {
+		return "", fmt.Errorf("no secret resolver registered for provider %q", ref.Provider)
+	}
+	return resolver.Resolve(ctx, ref)
+}
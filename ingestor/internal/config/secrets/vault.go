@@ -0,0 +1,170 @@
+// internal/config/secrets/vault.go
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VaultResolver resolves a SecretRef against a KV v2 secrets engine: Path is
+// the secret path with its mount as the first segment (e.g.
+// "secret/echoarb/kalshi", read over HTTP from "secret/data/echoarb/kalshi")
+// and Field selects one key out of that version's data map. Authentication is either a static
+// Token or AppRole (RoleID/SecretID exchanged for a token on first use and
+// refreshed once it's close to expiring); set exactly one.
+type VaultResolver struct {
+	Addr      string
+	Namespace string
+	Token     string // static token auth; leave empty to use AppRole
+	RoleID    string // AppRole auth
+	SecretID  string
+
+	HTTPClient *http.Client
+
+	mu          sync.Mutex
+	leaseToken  string
+	leaseExpiry time.Time
+}
+
+// vaultTokenRenewalMargin re-authenticates an AppRole login this long
+// before its lease actually expires, so a resolve in flight doesn't race a
+// lease that dies mid-request.
+const vaultTokenRenewalMargin = 30 * time.Second
+
+// NewVaultResolver creates a VaultResolver. Pass token for static-token
+// auth, or roleID/secretID for AppRole auth; leave the other pair zero.
+func NewVaultResolver(addr, namespace, token, roleID, secretID string) *VaultResolver {
+	return &VaultResolver{
+		Addr:       addr,
+		Namespace:  namespace,
+		Token:      token,
+		RoleID:     roleID,
+		SecretID:   secretID,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (v *VaultResolver) Resolve(ctx context.Context, ref SecretRef) (string, error) {
+	if ref.Field == "" {
+		return "", fmt.Errorf("vault secret ref %s: field is required for KV v2 lookups", ref)
+	}
+
+	token, err := v.authToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("vault auth: %w", err)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := v.request(ctx, token, http.MethodGet, kvDataPath(ref.Path), nil, &body); err != nil {
+		return "", fmt.Errorf("read %s: %w", ref, err)
+	}
+
+	value, ok := body.Data.Data[ref.Field]
+	if !ok {
+		return "", fmt.Errorf("field %q not found at vault path %s", ref.Field, ref.Path)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("field %q at vault path %s is not a string", ref.Field, ref.Path)
+	}
+	return str, nil
+}
+
+// authToken returns the static token if configured, otherwise a cached
+// AppRole login token, re-logging in once the cached one is within
+// vaultTokenRenewalMargin of expiring.
+func (v *VaultResolver) authToken(ctx context.Context) (string, error) {
+	if v.Token != "" {
+		return v.Token, nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.leaseToken != "" && time.Now().Add(vaultTokenRenewalMargin).Before(v.leaseExpiry) {
+		return v.leaseToken, nil
+	}
+
+	loginReq := map[string]string{"role_id": v.RoleID, "secret_id": v.SecretID}
+	var loginResp struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := v.request(ctx, "", http.MethodPost, "auth/approle/login", loginReq, &loginResp); err != nil {
+		return "", fmt.Errorf("approle login: %w", err)
+	}
+	if loginResp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("approle login returned no client_token")
+	}
+
+	v.leaseToken = loginResp.Auth.ClientToken
+	v.leaseExpiry = time.Now().Add(time.Duration(loginResp.Auth.LeaseDuration) * time.Second)
+	return v.leaseToken, nil
+}
+
+// request issues a Vault HTTP API call against vaultPath (relative to
+// v1/), optionally authenticated with token, and decodes the JSON response
+// body into out.
+func (v *VaultResolver) request(ctx context.Context, token, method, vaultPath string, reqBody interface{}, out interface{}) error {
+	var body bytes.Buffer
+	if reqBody != nil {
+		if err := json.NewEncoder(&body).Encode(reqBody); err != nil {
+			return fmt.Errorf("encode request body: %w", err)
+		}
+	}
+
+	url := fmt.Sprintf("%s/v1/%s", strings.TrimRight(v.Addr, "/"), vaultPath)
+	req, err := http.NewRequestWithContext(ctx, method, url, &body)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	resp, err := v.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("vault returned %s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// kvDataPath inserts KV v2's "data/" segment after the mount, so callers
+// can configure SecretRef.Path the same way they'd address the secret in
+// `vault kv get` (e.g. "secret/echoarb/kalshi") rather than the engine's
+// literal HTTP path.
+func kvDataPath(kvPath string) string {
+	kvPath = strings.Trim(kvPath, "/")
+	mount, rest, found := strings.Cut(kvPath, "/")
+	if !found {
+		return path.Join(mount, "data")
+	}
+	return path.Join(mount, "data", rest)
+}
@@ -0,0 +1,245 @@
+// internal/config/watcher.go
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// subscriptionReloadDebounce absorbs bursts of filesystem events - editors
+// commonly write a file in several syscalls (truncate, write, rename) that
+// would otherwise each trigger their own reload.
+const subscriptionReloadDebounce = 200 * time.Millisecond
+
+// SubscriptionDelta is the diff between the last-applied MarketSubscription
+// set and a newly (re)loaded one, keyed by MarketSubscription.ID.
+type SubscriptionDelta struct {
+	Added   []MarketSubscription
+	Removed []MarketSubscription
+	Changed []MarketSubscription
+}
+
+// Empty reports whether the delta has nothing to apply.
+func (d SubscriptionDelta) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// GroupDelta pairs a SubscriptionDelta with the SubscriptionGroup.Name it
+// belongs to, so a hot-reload only gets applied to that group's connectors
+// instead of leaking into every group sharing the process.
+type GroupDelta struct {
+	GroupName string
+	Delta     SubscriptionDelta
+}
+
+// Watcher watches CONFIG_PATH for changes - via fsnotify, and a SIGHUP as a
+// fallback for mounts that don't emit inotify events (e.g. some container
+// bind mounts) - and emits a GroupDelta on Deltas() each time a group's
+// subscription list changes. A reload that fails to parse or re-validate is
+// rejected and logged; the last-good subscription set is left running
+// rather than torn down by a bad edit.
+type Watcher struct {
+	path   string
+	logger *zap.SugaredLogger
+	deltas chan GroupDelta
+
+	mu      sync.Mutex
+	current map[string]map[string]MarketSubscription // group name -> subscription ID -> subscription
+}
+
+// NewWatcher creates a Watcher seeded with cfg's currently-loaded groups, so
+// the first file change is diffed against what's actually running rather
+// than an empty set.
+func NewWatcher(cfg *Config, logger *zap.SugaredLogger) *Watcher {
+	current := make(map[string]map[string]MarketSubscription, len(cfg.Groups))
+	for _, g := range cfg.Groups {
+		current[g.Name] = indexSubscriptions(g.Subscriptions)
+	}
+	// reload() can emit one GroupDelta per group in a single pass, so the
+	// channel needs a slot per group - otherwise the 2nd+ delta from the
+	// same reload is dropped by the non-blocking send below before the
+	// consumer even gets a chance to drain the first one.
+	deltaBuf := len(cfg.Groups)
+	if deltaBuf < 1 {
+		deltaBuf = 1
+	}
+	return &Watcher{
+		path:    getEnv("CONFIG_PATH", "./config/market_pairs.json"),
+		logger:  logger,
+		deltas:  make(chan GroupDelta, deltaBuf),
+		current: current,
+	}
+}
+
+// Deltas returns the channel GroupDelta events are emitted on.
+func (w *Watcher) Deltas() <-chan GroupDelta {
+	return w.deltas
+}
+
+// Run watches the config file until ctx is canceled or the fsnotify watcher
+// fails to start.
+func (w *Watcher) Run(ctx context.Context) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create fsnotify watcher: %w", err)
+	}
+	defer fsw.Close()
+
+	if err := fsw.Add(w.path); err != nil {
+		return fmt.Errorf("watch %s: %w", w.path, err)
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	var debounce *time.Timer
+	reload := make(chan struct{}, 1)
+	scheduleReload := func() {
+		if debounce == nil {
+			debounce = time.AfterFunc(subscriptionReloadDebounce, func() {
+				select {
+				case reload <- struct{}{}:
+				default:
+				}
+			})
+			return
+		}
+		debounce.Reset(subscriptionReloadDebounce)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				scheduleReload()
+			}
+
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			w.logger.Warnf("Config watcher error: %v", err)
+
+		case <-sighup:
+			scheduleReload()
+
+		case <-reload:
+			w.reload()
+		}
+	}
+}
+
+// reload re-reads and re-validates the subscription file (legacy flat or
+// grouped - same parseSubscriptionGroups Load uses), diffs each group
+// against its last-good set, and emits a GroupDelta per group that changed.
+// A file that doesn't parse or fails validation is logged and ignored - the
+// last-good set (w.current) is left untouched, so operators editing the
+// file in place can't accidentally drop every running feed with a typo.
+func (w *Watcher) reload() {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		w.logger.Warnf("Failed to read %s, keeping last-good subscriptions: %v", w.path, err)
+		return
+	}
+
+	groups, err := parseSubscriptionGroups(data)
+	if err != nil {
+		w.logger.Warnf("Failed to parse %s, keeping last-good subscriptions: %v", w.path, err)
+		return
+	}
+	for _, g := range groups {
+		if err := validateSubscriptions(g.Subscriptions); err != nil {
+			w.logger.Warnf("Rejected group %q in %s, keeping last-good subscriptions: %v", g.Name, w.path, err)
+			return
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, g := range groups {
+		next := indexSubscriptions(g.Subscriptions)
+		delta := diffSubscriptions(w.current[g.Name], next)
+		if delta.Empty() {
+			continue
+		}
+
+		w.current[g.Name] = next
+		w.logger.Infof("Group %q subscriptions changed: %d added, %d removed, %d changed", g.Name, len(delta.Added), len(delta.Removed), len(delta.Changed))
+
+		select {
+		case w.deltas <- GroupDelta{GroupName: g.Name, Delta: delta}:
+		default:
+			w.logger.Warnf("Subscription delta channel full, dropping a reload for group %q - consumer is falling behind", g.Name)
+		}
+	}
+}
+
+func indexSubscriptions(subs []MarketSubscription) map[string]MarketSubscription {
+	index := make(map[string]MarketSubscription, len(subs))
+	for _, s := range subs {
+		index[s.ID] = s
+	}
+	return index
+}
+
+func diffSubscriptions(current, next map[string]MarketSubscription) SubscriptionDelta {
+	var delta SubscriptionDelta
+	for id, sub := range next {
+		old, existed := current[id]
+		switch {
+		case !existed:
+			delta.Added = append(delta.Added, sub)
+		case !subscriptionEqual(old, sub):
+			delta.Changed = append(delta.Changed, sub)
+		}
+	}
+	for id, sub := range current {
+		if _, stillPresent := next[id]; !stillPresent {
+			delta.Removed = append(delta.Removed, sub)
+		}
+	}
+	return delta
+}
+
+func subscriptionEqual(a, b MarketSubscription) bool {
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return string(aJSON) == string(bJSON)
+}
+
+// validateSubscriptions re-checks a reloaded subscription list the same way
+// an operator would expect Load to: every entry needs a unique ID and at
+// least one exchange target.
+func validateSubscriptions(subs []MarketSubscription) error {
+	seen := make(map[string]bool, len(subs))
+	for _, s := range subs {
+		if s.ID == "" {
+			return fmt.Errorf("subscription missing id")
+		}
+		if seen[s.ID] {
+			return fmt.Errorf("duplicate subscription id %q", s.ID)
+		}
+		seen[s.ID] = true
+		if s.Kalshi == nil && s.Polymarket == nil && s.Manifold == nil {
+			return fmt.Errorf("subscription %q has no exchange target", s.ID)
+		}
+	}
+	return nil
+}
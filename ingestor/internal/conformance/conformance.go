@@ -0,0 +1,212 @@
+// Package conformance replays recorded exchange WebSocket frames through a
+// connector's message-handling path and checks the resulting tick stream
+// against a golden file, so schema drift on Kalshi or Polymarket shows up as
+// a failing assertion instead of a silent bad tick in production.
+//
+// A vector directory looks like:
+//
+//	testdata/vectors/kalshi/orderbook_delta/
+//	  frames.jsonl   one raw WS frame payload per line
+//	  expected.json  []ExpectedTick golden output, in publish order
+//
+// Set SKIP_CONFORMANCE=1 to skip replay (e.g. on a machine without the
+// corpus checked out). ECHOARB_VECTORS_BRANCH is reported by VectorsBranch
+// (default "main"); RunSuite does not read it and fetches nothing - it always
+// replays whatever vectors are on disk under vectorsDir.
+package conformance
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/models"
+)
+
+// Replayable is implemented by connectors that can be driven off a recorded
+// frame instead of a live socket read.
+type Replayable interface {
+	ProcessMessage(data []byte) error
+}
+
+// FakeRedis is a redis.Publisher that records ticks, contract metadata, and
+// book snapshots in memory instead of talking to a real Redis instance, so a
+// real connector can be constructed for replay without a live backend.
+type FakeRedis struct {
+	mu        sync.Mutex
+	ticks     []models.Tick
+	contracts []models.ContractInfo
+	books     []models.OrderbookSnapshot
+}
+
+// NewFakeRedis creates an empty in-memory recorder.
+func NewFakeRedis() *FakeRedis {
+	return &FakeRedis{}
+}
+
+// PublishTick records the tick instead of writing it to Redis.
+func (f *FakeRedis) PublishTick(tick *models.Tick) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ticks = append(f.ticks, *tick)
+	return nil
+}
+
+// PublishContractMeta records the contract metadata instead of writing it
+// to Redis.
+func (f *FakeRedis) PublishContractMeta(source, ticker string, info *models.ContractInfo) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.contracts = append(f.contracts, *info)
+	return nil
+}
+
+// PublishBookSnapshot records the book snapshot instead of writing it to
+// Redis.
+func (f *FakeRedis) PublishBookSnapshot(snap *models.OrderbookSnapshot) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.books = append(f.books, *snap)
+	return nil
+}
+
+// Ticks returns every tick published so far, in publish order.
+func (f *FakeRedis) Ticks() []models.Tick {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]models.Tick, len(f.ticks))
+	copy(out, f.ticks)
+	return out
+}
+
+// ExpectedTick is one row of a golden expected-ticks file.
+type ExpectedTick struct {
+	ContractID      string  `json:"contract_id"`
+	Price           float64 `json:"price"`
+	TimestampSource int64   `json:"ts_source"`
+}
+
+// Replay feeds every frame in vectorPath's frames.jsonl to connector in
+// order, as if each had just arrived off the socket. It does not stop on a
+// per-frame processing error (the live read loop doesn't either); instead it
+// returns the first error it saw, if any, after draining the whole file.
+func Replay(vectorPath string, connector Replayable) error {
+	framesPath := filepath.Join(vectorPath, "frames.jsonl")
+	f, err := os.Open(framesPath)
+	if err != nil {
+		return fmt.Errorf("open vector frames: %w", err)
+	}
+	defer f.Close()
+
+	var firstErr error
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := connector.ProcessMessage(line); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s: %w", framesPath, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("scan vector frames: %w", err)
+	}
+	return firstErr
+}
+
+// LoadExpected reads the golden expected-ticks file for a vector.
+func LoadExpected(vectorPath string) ([]ExpectedTick, error) {
+	data, err := os.ReadFile(filepath.Join(vectorPath, "expected.json"))
+	if err != nil {
+		return nil, fmt.Errorf("read expected.json: %w", err)
+	}
+	var expected []ExpectedTick
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return nil, fmt.Errorf("parse expected.json: %w", err)
+	}
+	return expected, nil
+}
+
+// AssertGolden compares got (as captured by a FakeRedis) against the golden
+// file in vectorPath, returning a descriptive error on the first mismatch.
+func AssertGolden(vectorPath string, got []models.Tick) error {
+	expected, err := LoadExpected(vectorPath)
+	if err != nil {
+		return err
+	}
+	if len(got) != len(expected) {
+		return fmt.Errorf("%s: got %d ticks, expected %d", vectorPath, len(got), len(expected))
+	}
+	for i, exp := range expected {
+		tick := got[i]
+		if tick.ContractID != exp.ContractID || tick.Price != exp.Price || tick.TimestampSource != exp.TimestampSource {
+			return fmt.Errorf("%s: tick %d mismatch: got {%s %v %d}, expected {%s %v %d}",
+				vectorPath, i, tick.ContractID, tick.Price, tick.TimestampSource,
+				exp.ContractID, exp.Price, exp.TimestampSource)
+		}
+	}
+	return nil
+}
+
+// CaseResult is the outcome of replaying one vector directory.
+type CaseResult struct {
+	Vector string
+	Err    error
+}
+
+// RunSuite discovers every vector directory under vectorsDir (one level per
+// exchange, one level per scenario) and replays each against a fresh
+// connector built by newConnector. newConnector must return a Replayable
+// wired to publish through a FakeRedis, and the FakeRedis so RunSuite can
+// read back what was published.
+//
+// Honors SKIP_CONFORMANCE=1 by returning an empty result set immediately.
+func RunSuite(vectorsDir string, newConnector func() (Replayable, *FakeRedis)) ([]CaseResult, error) {
+	if os.Getenv("SKIP_CONFORMANCE") == "1" {
+		return nil, nil
+	}
+
+	var vectors []string
+	err := filepath.WalkDir(vectorsDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if d.Name() == "frames.jsonl" {
+			vectors = append(vectors, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk vectors dir: %w", err)
+	}
+	sort.Strings(vectors)
+
+	results := make([]CaseResult, 0, len(vectors))
+	for _, vector := range vectors {
+		connector, fake := newConnector()
+		var caseErr error
+		if caseErr = Replay(vector, connector); caseErr == nil {
+			caseErr = AssertGolden(vector, fake.Ticks())
+		}
+		results = append(results, CaseResult{Vector: vector, Err: caseErr})
+	}
+	return results, nil
+}
+
+// VectorsBranch returns the corpus revision contributors should pull vectors
+// from, as set by ECHOARB_VECTORS_BRANCH, defaulting to "main".
+func VectorsBranch() string {
+	if branch := os.Getenv("ECHOARB_VECTORS_BRANCH"); branch != "" {
+		return branch
+	}
+	return "main"
+}
@@ -0,0 +1,124 @@
+package conformance_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/auditlog"
+	"github.com/dragonuber/echoarb/ingestor/internal/config"
+	"github.com/dragonuber/echoarb/ingestor/internal/config/secrets"
+	"github.com/dragonuber/echoarb/ingestor/internal/conformance"
+	"github.com/dragonuber/echoarb/ingestor/internal/connectors"
+	"github.com/dragonuber/echoarb/ingestor/internal/metrics"
+	"go.uber.org/zap"
+)
+
+// testKalshiConfig builds a minimal Config good enough to construct a real
+// KalshiConnector: a throwaway RSA key resolved through the env provider,
+// since Load isn't exercised here and there's no real Kalshi account to
+// authenticate against. Replay never opens a socket, so the key is only
+// ever parsed, never used to sign a request.
+func testKalshiConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate test key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal test key: %v", err)
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	t.Setenv("CONFORMANCE_TEST_KALSHI_API_KEY", "test-key-id")
+	t.Setenv("CONFORMANCE_TEST_KALSHI_PRIVATE_KEY", string(pemBytes))
+
+	return &config.Config{
+		KalshiAPIKeyRef:     secrets.SecretRef{Provider: secrets.ProviderEnv, Path: "CONFORMANCE_TEST_KALSHI_API_KEY"},
+		KalshiPrivateKeyRef: secrets.SecretRef{Provider: secrets.ProviderEnv, Path: "CONFORMANCE_TEST_KALSHI_PRIVATE_KEY"},
+		Secrets:             secrets.NewCachedResolver(secrets.DefaultRegistry(context.Background()), 0),
+		Compression:         "none",
+	}
+}
+
+func testLogger(t *testing.T) *zap.SugaredLogger {
+	t.Helper()
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		t.Fatalf("create logger: %v", err)
+	}
+	return logger.Sugar()
+}
+
+func testAuditLog(t *testing.T) *auditlog.Logger {
+	t.Helper()
+	al, err := auditlog.New(config.AuditLogConfig{Enabled: false})
+	if err != nil {
+		t.Fatalf("create disabled audit log: %v", err)
+	}
+	return al
+}
+
+// TestKalshiConformance replays every recorded Kalshi vector through a real
+// KalshiConnector and checks the resulting ticks against each vector's
+// golden file.
+func TestKalshiConformance(t *testing.T) {
+	cfg := testKalshiConfig(t)
+	logger := testLogger(t)
+	metricsReg := metrics.NewRegistry()
+	auditLogger := testAuditLog(t)
+	group := config.SubscriptionGroup{Name: "default"}
+
+	results, err := conformance.RunSuite("testdata/vectors/kalshi", func() (conformance.Replayable, *conformance.FakeRedis) {
+		fake := conformance.NewFakeRedis()
+		conn, err := connectors.NewKalshiConnector(context.Background(), cfg, group, fake, nil, auditLogger, metricsReg, logger)
+		if err != nil {
+			t.Fatalf("construct Kalshi connector: %v", err)
+		}
+		return conn, fake
+	})
+	if err != nil {
+		t.Fatalf("run kalshi conformance suite: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("no kalshi vectors found")
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: %v", r.Vector, r.Err)
+		}
+	}
+}
+
+// TestPolymarketConformance replays every recorded Polymarket vector through
+// a real PolymarketConnector and checks the resulting ticks against each
+// vector's golden file.
+func TestPolymarketConformance(t *testing.T) {
+	cfg := &config.Config{Compression: "none"}
+	logger := testLogger(t)
+	metricsReg := metrics.NewRegistry()
+	auditLogger := testAuditLog(t)
+	group := config.SubscriptionGroup{Name: "default"}
+
+	results, err := conformance.RunSuite("testdata/vectors/polymarket", func() (conformance.Replayable, *conformance.FakeRedis) {
+		fake := conformance.NewFakeRedis()
+		conn := connectors.NewPolymarketConnector(cfg, group, fake, nil, auditLogger, metricsReg, logger)
+		return conn, fake
+	})
+	if err != nil {
+		t.Fatalf("run polymarket conformance suite: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("no polymarket vectors found")
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: %v", r.Vector, r.Err)
+		}
+	}
+}
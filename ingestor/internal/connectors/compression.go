@@ -0,0 +1,33 @@
+// internal/connectors/compression.go
+package connectors
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// Compression modes a Transport can be configured with.
+const (
+	CompressionNone    = "none"
+	CompressionGzip    = "gzip"
+	CompressionDeflate = "deflate"
+)
+
+// GzipDecompress decompresses a whole-message gzip-compressed WebSocket frame
+// payload, as sent by venues (or proxies in front of them) that gzip each
+// frame rather than negotiating permessage-deflate at the protocol level.
+func GzipDecompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gzip reader: %w", err)
+	}
+	defer reader.Close()
+
+	out, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("gzip decompress: %w", err)
+	}
+	return out, nil
+}
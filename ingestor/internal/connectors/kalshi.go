@@ -5,282 +5,404 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"sync"
 	"time"
 
-	"github.com/gorilla/websocket"
+	"github.com/dragonuber/echoarb/ingestor/internal/auditlog"
 	"github.com/dragonuber/echoarb/ingestor/internal/auth"
 	"github.com/dragonuber/echoarb/ingestor/internal/config"
 	"github.com/dragonuber/echoarb/ingestor/internal/metrics"
 	"github.com/dragonuber/echoarb/ingestor/internal/models"
+	"github.com/dragonuber/echoarb/ingestor/internal/orderbook"
 	"github.com/dragonuber/echoarb/ingestor/internal/redis"
 	"github.com/dragonuber/echoarb/ingestor/internal/retry"
+	"github.com/dragonuber/echoarb/ingestor/internal/shm"
+	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
 )
 
 const (
 	kalshiSource = "KALSHI"
 	pingInterval = 30 * time.Second
-	pongTimeout  = 60 * time.Second  // Must be > pingInterval to handle idle markets
+	pongTimeout  = 60 * time.Second // Must be > pingInterval to handle idle markets
+
+	// kalshiCredentialRotationInterval is how often WatchCredentials polls
+	// cfg.Secrets for a rotated Kalshi key pair. It's the same as
+	// config.SecretCacheTTL, so polling more often than this would just
+	// re-read the same cached value.
+	kalshiCredentialRotationInterval = config.SecretCacheTTL
 )
 
-// KalshiConnector handles Kalshi WebSocket connection
+// KalshiConnector wires a KalshiHandler up to a shared Transport.
 type KalshiConnector struct {
-	config       *config.Config
-	auth         *auth.KalshiAuth
-	redis        *redis.Client
-	metrics      *metrics.Registry
-	logger       *zap.SugaredLogger
-	
-	// State management
-	lastPrices   sync.Map // map[string]float64 for deduplication
-	isConnected  bool
-	mu           sync.RWMutex
+	transport *Transport
+	handler   *KalshiHandler
 }
 
-// NewKalshiConnector creates a new Kalshi connector
+// NewKalshiConnector creates a new Kalshi connector for one SubscriptionGroup.
+// Credentials are resolved fresh from group via cfg.ResolveGroupCredentials,
+// rather than read off cfg.KalshiAPIKey/KalshiPrivateKeyPEM directly, so a
+// group with its own CredentialsRef gets its own signer.
 func NewKalshiConnector(
+	ctx context.Context,
 	cfg *config.Config,
-	redisClient *redis.Client,
+	group config.SubscriptionGroup,
+	redisClient redis.Publisher,
+	shmWriter *shm.Writer,
+	auditLogger *auditlog.Logger,
 	metricsReg *metrics.Registry,
 	logger *zap.SugaredLogger,
 ) (*KalshiConnector, error) {
-	// Initialize Kalshi authentication
-	auth, err := auth.NewKalshiAuth(cfg.KalshiAPIKey, cfg.KalshiPrivateKeyPEM)
+	apiKey, privateKeyPEM, err := cfg.ResolveGroupCredentials(ctx, group)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize Kalshi auth: %w", err)
+		return nil, fmt.Errorf("failed to resolve Kalshi credentials for group %q: %w", group.Name, err)
+	}
+	kalshiAuth, err := auth.NewKalshiAuthFromPEM(apiKey, privateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Kalshi auth for group %q: %w", group.Name, err)
 	}
 
-	return &KalshiConnector{
-		config:  cfg,
-		auth:    auth,
-		redis:   redisClient,
-		metrics: metricsReg,
-		logger:  logger.Named("kalshi"),
-	}, nil
+	named := logger.Named("kalshi")
+	books := orderbook.NewStore(redisClient, named)
+	handler := NewKalshiHandler(cfg, group, kalshiAuth, redisClient, metricsReg, named, books)
+	transport := NewTransport(kalshiSource, group.Name, TransportConfig{
+		URL:              cfg.KalshiWSURL,
+		PingInterval:     pingInterval,
+		PongTimeout:      pongTimeout,
+		HandshakeTimeout: 30 * time.Second,
+		Compression:      cfg.Compression,
+	}, handler, redisClient, shmWriter, auditLogger, metricsReg, named)
+
+	return &KalshiConnector{transport: transport, handler: handler}, nil
 }
 
 // Start begins the connection loop with retry
 func (k *KalshiConnector) Start(ctx context.Context) {
-	k.logger.Info("Starting Kalshi connector")
+	k.transport.Start(ctx, k.retryConfig())
+}
 
-	retryCfg := retry.Config{
-		InitialInterval: k.config.Reconnect.InitialInterval,
-		MaxInterval:     k.config.Reconnect.MaxInterval,
-		MaxRetries:      k.config.Reconnect.MaxRetries,
+// retryConfig builds the retry.Config Start drives the transport with, split
+// out from Start so it can be asserted on directly in tests without actually
+// running the (blocking, networked) reconnect loop.
+func (k *KalshiConnector) retryConfig() retry.Config {
+	cfg := k.handler.config
+	return retry.Config{
+		InitialInterval: cfg.Reconnect.InitialInterval,
+		MaxInterval:     cfg.Reconnect.MaxInterval,
+		MaxRetries:      cfg.Reconnect.MaxRetries,
 		Multiplier:      2.0,
 		Jitter:          true,
+		JitterStrategy:  cfg.Reconnect.JitterStrategy,
+		Breaker:         cfg.Reconnect.Breaker(),
 	}
+}
 
-	retry.RetryForever(ctx, retryCfg, k.logger, "Kalshi", func() error {
-		return k.connect(ctx)
-	})
+// IsConnected reports whether the transport currently has a live connection.
+func (k *KalshiConnector) IsConnected() bool {
+	return k.transport.IsConnected()
+}
 
-	k.logger.Info("Kalshi connector stopped")
+// ApplySubscriptionDelta issues incremental subscribe/unsubscribe frames for
+// a hot-reloaded config change, without reconnecting.
+func (k *KalshiConnector) ApplySubscriptionDelta(delta config.SubscriptionDelta) error {
+	return k.transport.ApplySubscriptionDelta(delta)
 }
 
-// connect establishes WebSocket connection
-func (k *KalshiConnector) connect(ctx context.Context) error {
-	k.logger.Info("Connecting to Kalshi WebSocket...")
+// WatchCredentials runs the config's Kalshi credential rotation poll and
+// swaps the connector's signer in place whenever it fires, so a key
+// rotated in the configured secret backend reaches the live WebSocket
+// connection's auth headers without a process restart. It blocks until ctx
+// is canceled.
+func (k *KalshiConnector) WatchCredentials(ctx context.Context, logger *zap.SugaredLogger) error {
+	return k.handler.config.WatchGroupCredentials(ctx, k.handler.group, kalshiCredentialRotationInterval,
+		func(apiKey string, privateKeyPEM []byte) {
+			newAuth, err := auth.NewKalshiAuthFromPEM(apiKey, privateKeyPEM)
+			if err != nil {
+				logger.Errorf("Rotated kalshi credentials failed to decode, keeping previous signer: %v", err)
+				return
+			}
+			k.handler.setAuth(newAuth)
+			logger.Info("Rotated kalshi credentials")
+		},
+		func(err error) {
+			logger.Warnf("Failed to resolve kalshi credentials, keeping previous signer: %v", err)
+		},
+	)
+}
 
-	// Get authenticated headers
-	headers, err := k.auth.GetWebSocketHeaders()
-	if err != nil {
-		k.metrics.RecordError(kalshiSource, "auth_error")
-		return fmt.Errorf("failed to get auth headers: %w", err)
-	}
+// ProcessMessage is the exported entrypoint conformance replay drives vector
+// frames through; it runs the exact same path as the live read loop.
+func (k *KalshiConnector) ProcessMessage(data []byte) error {
+	return k.transport.ProcessAndPublish(data)
+}
 
-	// Create dialer with timeout
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 30 * time.Second,
-	}
+// KalshiHandler implements connectors.Handler for the Kalshi orderbook_delta
+// WebSocket feed. It is a pure parser over frame bytes plus subscribe
+// framing; Transport owns the socket.
+type KalshiHandler struct {
+	config  *config.Config
+	group   config.SubscriptionGroup
+	redis   redis.ContractMetaPublisher
+	metrics *metrics.Registry
+	logger  *zap.SugaredLogger
+	books   *orderbook.Store
+
+	authMu sync.RWMutex
+	auth   *auth.KalshiAuth // guarded by authMu - setAuth swaps it on a credential rotation
+
+	lastPrices sync.Map // map[string]float64 for deduplication
+	contracts  sync.Map // map[string]*models.ContractInfo, keyed by ticker
+
+	// pendingResyncs tracks tickers whose book hit a sequence gap (map[string]struct{}),
+	// drained by PendingResyncs so Transport can force the venue to push a
+	// fresh snapshot.
+	pendingResyncs sync.Map
+}
 
-	// Connect
-	conn, _, err := dialer.Dial(k.config.KalshiWSURL, headers)
-	if err != nil {
-		k.metrics.RecordConnection(kalshiSource, false)
-		k.metrics.RecordError(kalshiSource, "connection_error")
-		return fmt.Errorf("failed to connect: %w", err)
+// kalshiTickSize is Kalshi's price granularity: one cent, in probability space.
+const kalshiTickSize = 0.01
+
+// NewKalshiHandler creates a Kalshi message handler for one SubscriptionGroup.
+// bookStore maintains live per-ticker books reconstructed from the
+// orderbook_snapshot/delta channel; it is independent of redisClient's
+// per-tick publishing.
+func NewKalshiHandler(cfg *config.Config, group config.SubscriptionGroup, kalshiAuth *auth.KalshiAuth, redisClient redis.ContractMetaPublisher, metricsReg *metrics.Registry, logger *zap.SugaredLogger, bookStore *orderbook.Store) *KalshiHandler {
+	return &KalshiHandler{
+		config:  cfg,
+		group:   group,
+		auth:    kalshiAuth,
+		redis:   redisClient,
+		metrics: metricsReg,
+		logger:  logger,
+		books:   bookStore,
 	}
-	defer conn.Close()
+}
 
-	k.metrics.RecordConnection(kalshiSource, true)
-	k.setConnected(true)
-	k.logger.Info("Connected to Kalshi")
+// AuthHeaders signs the WebSocket handshake using the currently-configured
+// key pair, which may have been swapped in by setAuth since the handler
+// was created.
+func (h *KalshiHandler) AuthHeaders() (http.Header, error) {
+	h.authMu.RLock()
+	a := h.auth
+	h.authMu.RUnlock()
+	return a.GetWebSocketHeaders()
+}
 
-	// Set up ping/pong handlers
-	conn.SetPongHandler(func(string) error {
-		conn.SetReadDeadline(time.Now().Add(pongTimeout))
-		return nil
-	})
+// setAuth swaps in a new signer, e.g. after WatchCredentials observes a
+// rotated Kalshi key pair. It only takes effect on the next reconnect or
+// signed request - an already-open WebSocket connection isn't re-signed.
+func (h *KalshiHandler) setAuth(a *auth.KalshiAuth) {
+	h.authMu.Lock()
+	h.auth = a
+	h.authMu.Unlock()
+}
 
-	// Subscribe to markets
-	if err := k.subscribe(conn); err != nil {
-		return fmt.Errorf("failed to subscribe: %w", err)
+// Subscribe subscribes to every market in this handler's SubscriptionGroup.
+func (h *KalshiHandler) Subscribe(conn *websocket.Conn) error {
+	for _, sub := range h.group.Subscriptions {
+		if err := h.SubscribeMarket(conn, sub); err != nil {
+			return err
+		}
 	}
 
-	// Start ping routine
-	pingCtx, pingCancel := context.WithCancel(ctx)
-	defer pingCancel()
-	go k.pingLoop(pingCtx, conn)
-
-	// Read messages
-	return k.readLoop(ctx, conn)
+	return nil
 }
 
-// subscribe subscribes to market updates
-func (k *KalshiConnector) subscribe(conn *websocket.Conn) error {
-	for _, pair := range k.config.Pairs {
-		if pair.Kalshi == nil {
-			continue
-		}
+// SubscribeMarket subscribes to a single market on an already-open
+// connection, so a config hot-reload can add a market without a reconnect.
+func (h *KalshiHandler) SubscribeMarket(conn *websocket.Conn, sub config.MarketSubscription) error {
+	if sub.Kalshi == nil {
+		return nil
+	}
 
-		subscribeMsg := map[string]interface{}{
-			"id":  1,
-			"cmd": "subscribe",
-			"params": map[string]interface{}{
-				"channels":      []string{"orderbook_delta"},
-				"market_ticker": pair.Kalshi.Ticker,
-			},
-		}
+	subscribeMsg := map[string]interface{}{
+		"id":  1,
+		"cmd": "subscribe",
+		"params": map[string]interface{}{
+			"channels":      []string{"orderbook_delta", "orderbook_snapshot"},
+			"market_ticker": sub.Kalshi.Ticker,
+		},
+	}
 
-		if err := conn.WriteJSON(subscribeMsg); err != nil {
-			return fmt.Errorf("failed to subscribe to %s: %w", pair.Kalshi.Ticker, err)
-		}
+	if err := conn.WriteJSON(subscribeMsg); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", sub.Kalshi.Ticker, err)
+	}
 
-		k.logger.Infof("Subscribed to Kalshi market: %s", pair.Kalshi.Ticker)
+	info := &models.ContractInfo{
+		Ticker:        sub.Kalshi.Ticker,
+		PriceTickSize: kalshiTickSize,
+		QuoteCurrency: "USD",
+	}
+	h.contracts.Store(sub.Kalshi.Ticker, info)
+	if err := h.redis.PublishContractMeta(kalshiSource, sub.Kalshi.Ticker, info); err != nil {
+		h.logger.Warnf("Failed to publish contract meta for %s: %v", sub.Kalshi.Ticker, err)
 	}
 
+	h.logger.Infof("Subscribed to Kalshi market: %s", sub.Kalshi.Ticker)
 	return nil
 }
 
-// pingLoop sends periodic pings
-func (k *KalshiConnector) pingLoop(ctx context.Context, conn *websocket.Conn) {
-	ticker := time.NewTicker(pingInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			if err := conn.WriteControl(
-				websocket.PingMessage,
-				[]byte{},
-				time.Now().Add(10*time.Second),
-			); err != nil {
-				k.logger.Warnf("Ping failed: %v", err)
-				return
-			}
-		}
+// UnsubscribeMarket unsubscribes from a single market on an already-open
+// connection, so a config hot-reload can drop a market without a reconnect.
+func (h *KalshiHandler) UnsubscribeMarket(conn *websocket.Conn, sub config.MarketSubscription) error {
+	if sub.Kalshi == nil {
+		return nil
 	}
-}
-
-// readLoop reads messages from WebSocket
-func (k *KalshiConnector) readLoop(ctx context.Context, conn *websocket.Conn) error {
-	defer k.setConnected(false)
 
-	// Set initial read deadline
-	conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	unsubscribeMsg := map[string]interface{}{
+		"id":  1,
+		"cmd": "unsubscribe",
+		"params": map[string]interface{}{
+			"market_ticker": sub.Kalshi.Ticker,
+		},
+	}
 
-	for {
-		// Check context
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
+	if err := conn.WriteJSON(unsubscribeMsg); err != nil {
+		return fmt.Errorf("failed to unsubscribe from %s: %w", sub.Kalshi.Ticker, err)
+	}
 
-		// Read message
-		_, message, err := conn.ReadMessage()
-		if err != nil {
-			k.metrics.RecordError(kalshiSource, "read_error")
-			return fmt.Errorf("read error: %w", err)
-		}
+	h.contracts.Delete(sub.Kalshi.Ticker)
+	h.logger.Infof("Unsubscribed from Kalshi market: %s", sub.Kalshi.Ticker)
+	return nil
+}
 
-		// Process message
-		if err := k.processMessage(message); err != nil {
-			k.logger.Warnf("Failed to process message: %v", err)
-			k.metrics.RecordError(kalshiSource, "process_error")
-			// Continue reading despite processing errors
+// PendingResyncs drains and returns the set of MarketSubscriptions whose
+// book hit a sequence gap since the last call, so Transport can force a
+// fresh snapshot via an unsubscribe/subscribe cycle. A ticker with no
+// matching entry in h.group.Subscriptions (e.g. it was removed from the
+// group since) is silently dropped - there's nothing left to resubscribe.
+func (h *KalshiHandler) PendingResyncs() []config.MarketSubscription {
+	var subs []config.MarketSubscription
+	h.pendingResyncs.Range(func(key, _ interface{}) bool {
+		ticker := key.(string)
+		h.pendingResyncs.Delete(ticker)
+		for _, sub := range h.group.Subscriptions {
+			if sub.Kalshi != nil && sub.Kalshi.Ticker == ticker {
+				subs = append(subs, sub)
+				break
+			}
 		}
-	}
+		return true
+	})
+	return subs
 }
 
-// processMessage processes a single message
-func (k *KalshiConnector) processMessage(data []byte) error {
-	startTime := time.Now()
-
-	// Parse JSON
+// ProcessMessage parses a single Kalshi WS frame into zero or one tick.
+func (h *KalshiHandler) ProcessMessage(data []byte) ([]*models.Tick, error) {
 	var msg map[string]interface{}
 	if err := json.Unmarshal(data, &msg); err != nil {
-		return fmt.Errorf("json unmarshal error: %w", err)
+		return nil, fmt.Errorf("json unmarshal error: %w", err)
 	}
 
 	// Check message type
 	msgType, _ := msg["type"].(string)
+
+	// "orderbook_snapshot" and a full-book "orderbook_delta" (one carrying a
+	// "updates" array of level-by-level changes) feed the level-2 book
+	// reconstruction in h.books, maintained separately from the summary
+	// yes_bid/yes_ask quotes handled below. Replay vectors predate the
+	// "updates" field, so they keep taking the summary path untouched.
+	if msgType == "orderbook_snapshot" {
+		return h.processOrderbookSnapshot(data)
+	}
+	if msgType == "orderbook_delta" {
+		if _, hasUpdates := msg["updates"]; hasUpdates {
+			return h.processOrderbookDelta(data)
+		}
+	}
+
 	if msgType != "orderbook_delta" {
-		return nil // Ignore non-orderbook messages
+		return nil, nil // Ignore non-orderbook messages
 	}
 
 	// Extract fields
 	ticker, _ := msg["ticker"].(string)
 	if ticker == "" {
-		return fmt.Errorf("missing ticker")
+		return nil, fmt.Errorf("missing ticker")
 	}
 
 	yesBid, _ := msg["yes_bid"].(float64)
 	yesAsk, _ := msg["yes_ask"].(float64)
 	sourceTS, _ := msg["ts"].(float64)
 
-	// Calculate mid-price (convert from cents to probability)
-	price := (yesBid + yesAsk) / 200.0
+	// Normalize using this contract's tick size instead of a hardcoded
+	// cents-to-probability conversion; fall back to the default Kalshi tick
+	// size if Subscribe hasn't populated contract metadata yet (e.g. replay).
+	tickSize := kalshiTickSize
+	if info, ok := h.contracts.Load(ticker); ok {
+		tickSize = info.(*models.ContractInfo).PriceTickSize
+	}
+	bestBid := yesBid * tickSize
+	bestAsk := yesAsk * tickSize
+	price := (bestBid + bestAsk) / 2.0
 
 	// Check for duplicate
-	cacheKey := ticker
-	if lastPrice, ok := k.lastPrices.Load(cacheKey); ok {
+	if lastPrice, ok := h.lastPrices.Load(ticker); ok {
 		if lastPrice.(float64) == price {
-			k.metrics.RecordDuplicate(kalshiSource)
-			return nil
+			h.metrics.RecordDuplicate(kalshiSource)
+			return nil, nil
 		}
 	}
-	k.lastPrices.Store(cacheKey, price)
+	h.lastPrices.Store(ticker, price)
 
-	// Create tick
 	tick := &models.Tick{
 		Source:          kalshiSource,
 		ContractID:      ticker,
 		Price:           price,
+		BestBid:         bestBid,
+		BestAsk:         bestAsk,
+		MidPrice:        price,
+		TickSize:        tickSize,
 		TimestampSource: int64(sourceTS),
 		TimestampIngest: time.Now().UnixMilli(),
 	}
 
-	// Publish to Redis
-	if err := k.redis.PublishTick(tick); err != nil {
-		k.metrics.RecordError(kalshiSource, "redis_error")
-		return fmt.Errorf("failed to publish: %w", err)
+	return []*models.Tick{tick}, nil
+}
+
+// processOrderbookSnapshot applies a full level-2 snapshot to the book store
+// (if configured) and reports the resulting book state; it returns no ticks
+// of its own since Book.ApplySnapshot doesn't derive one until the next delta.
+func (h *KalshiHandler) processOrderbookSnapshot(data []byte) ([]*models.Tick, error) {
+	if h.books == nil {
+		return nil, nil
 	}
 
-	// Record metrics
-	k.metrics.RecordMessage(kalshiSource, tick.TimestampSource, true)
-	k.metrics.RecordProcessingTime(kalshiSource, time.Since(startTime))
-	k.metrics.RecordPrice(kalshiSource, ticker, price)
+	var snap models.OrderbookSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("json unmarshal orderbook_snapshot error: %w", err)
+	}
+	if snap.Ticker == "" {
+		return nil, fmt.Errorf("missing ticker in orderbook_snapshot")
+	}
 
-	return nil
+	if err := h.books.HandleSnapshot(&snap); err != nil {
+		return nil, fmt.Errorf("failed to apply orderbook snapshot for %s: %w", snap.Ticker, err)
+	}
+	return nil, nil
 }
 
-// Helper methods
+// processOrderbookDelta applies a level-2 delta to the book store (if
+// configured) and returns the derived best-bid/ask tick for the resulting
+// book, whether or not the update changed the top of book.
+func (h *KalshiHandler) processOrderbookDelta(data []byte) ([]*models.Tick, error) {
+	if h.books == nil {
+		return nil, nil
+	}
 
-func (k *KalshiConnector) setConnected(connected bool) {
-	k.mu.Lock()
-	defer k.mu.Unlock()
-	k.isConnected = connected
-	k.metrics.SetConnectionActive(kalshiSource, connected)
-	k.metrics.SetHealthStatus("kalshi", connected)
-}
+	var delta models.OrderbookDelta
+	if err := json.Unmarshal(data, &delta); err != nil {
+		return nil, fmt.Errorf("json unmarshal orderbook_delta error: %w", err)
+	}
+	if delta.Ticker == "" {
+		return nil, fmt.Errorf("missing ticker in orderbook_delta")
+	}
 
-func (k *KalshiConnector) IsConnected() bool {
-	k.mu.RLock()
-	defer k.mu.RUnlock()
-	return k.isConnected
-}
\ No newline at end of file
+	ticks, err := h.books.HandleDelta(&delta)
+	if err != nil {
+		h.pendingResyncs.Store(delta.Ticker, struct{}{})
+		return nil, fmt.Errorf("failed to apply orderbook delta for %s: %w", delta.Ticker, err)
+	}
+	return ticks, nil
+}
@@ -0,0 +1,45 @@
+package connectors
+
+import (
+	"testing"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/config"
+	"github.com/dragonuber/echoarb/ingestor/internal/conformance"
+	"github.com/dragonuber/echoarb/ingestor/internal/metrics"
+	"go.uber.org/zap"
+)
+
+// TestKalshiHandlerProcessMessage exercises KalshiHandler.ProcessMessage
+// directly, with no socket or Redis involved - the point of splitting
+// parsing out of Transport in the first place.
+func TestKalshiHandlerProcessMessage(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	handler := NewKalshiHandler(&config.Config{}, config.SubscriptionGroup{}, nil, conformance.NewFakeRedis(), metrics.NewRegistry(), logger, nil)
+
+	ticks, err := handler.ProcessMessage([]byte(`{"type":"orderbook_delta","ticker":"INXD-24DEC31-B5000","yes_bid":62,"yes_ask":64,"ts":1735603200000}`))
+	if err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	if len(ticks) != 1 {
+		t.Fatalf("expected 1 tick, got %d", len(ticks))
+	}
+	if ticks[0].ContractID != "INXD-24DEC31-B5000" {
+		t.Errorf("unexpected contract id %q", ticks[0].ContractID)
+	}
+	if ticks[0].Price != 0.63 {
+		t.Errorf("unexpected price %v", ticks[0].Price)
+	}
+}
+
+func TestKalshiHandlerProcessMessageIgnoresNonOrderbook(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	handler := NewKalshiHandler(&config.Config{}, config.SubscriptionGroup{}, nil, conformance.NewFakeRedis(), metrics.NewRegistry(), logger, nil)
+
+	ticks, err := handler.ProcessMessage([]byte(`{"type":"subscribed","id":1,"channel":"orderbook_delta"}`))
+	if err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	if ticks != nil {
+		t.Errorf("expected no ticks for a non-orderbook message, got %d", len(ticks))
+	}
+}
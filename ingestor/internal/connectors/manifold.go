@@ -15,49 +15,102 @@ import (
 	"github.com/dragonuber/echoarb/ingestor/internal/metrics"
 	"github.com/dragonuber/echoarb/ingestor/internal/models"
 	"github.com/dragonuber/echoarb/ingestor/internal/redis"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/zap"
 )
 
 const (
-	manifoldSource        = "MANIFOLD"
-	manifoldPollInterval  = 2 * time.Second
-	manifoldFastInterval  = 500 * time.Millisecond
-	manifoldVolatilityThreshold = 0.02 // 2% change triggers fast polling
+	manifoldSource = "MANIFOLD"
+
+	// Per-slug polling buckets. A slug is placed in whichever bucket its EWMA
+	// of absolute probability change falls into; a single volatile market no
+	// longer forces fast polling of every quiet one, and vice versa.
+	manifoldFastInterval   = 500 * time.Millisecond
+	manifoldNormalInterval = 2 * time.Second
+	manifoldSlowInterval   = 10 * time.Second
+
+	manifoldFastThreshold = 0.02  // EWMA above this polls at manifoldFastInterval
+	manifoldSlowThreshold = 0.002 // EWMA below this polls at manifoldSlowInterval; between the two is "normal"
+
+	manifoldEWMAAlpha = 0.3 // weight given to the newest observation
+
+	// manifoldTickInterval is how often the poll loop wakes up to check
+	// which slugs are due; it must be <= manifoldFastInterval.
+	manifoldTickInterval = manifoldFastInterval
+
+	// manifoldControlChannel is the Redis pub/sub channel operators can
+	// publish {"action":"add"|"remove","slug":"..."} messages to, to change
+	// the polled slug set without restarting the connector.
+	manifoldControlChannel = "manifold:control"
+)
+
+// manifoldPollIntervalSeconds exposes each slug's current polling interval,
+// and manifoldVolatilityEWMA its last-observed EWMA of absolute probability
+// change, so operators can see the adaptive buckets in Grafana.
+var (
+	manifoldPollIntervalSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "echoarb_manifold_poll_interval_seconds",
+		Help: "Current polling interval for a Manifold slug",
+	}, []string{"slug"})
+
+	manifoldVolatilityEWMA = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "echoarb_manifold_volatility_ewma",
+		Help: "EWMA of absolute probability change for a Manifold slug",
+	}, []string{"slug"})
 )
 
-// ManifoldConnector handles Manifold API polling
+// slugBucket tracks one slug's adaptive polling state.
+type slugBucket struct {
+	mu       sync.Mutex
+	interval time.Duration
+	ewma     float64
+	nextPoll time.Time
+}
+
+// ManifoldConnector handles Manifold API polling for one SubscriptionGroup.
 type ManifoldConnector struct {
-	config      *config.Config
-	redis       *redis.Client
-	metrics     *metrics.Registry
-	logger      *zap.SugaredLogger
-	httpClient  *http.Client
-	lastPrices  sync.Map
-	isActive    bool
-	mu          sync.RWMutex
+	config     *config.Config
+	group      config.SubscriptionGroup
+	redis      *redis.Client
+	metrics    *metrics.Registry
+	logger     *zap.SugaredLogger
+	httpClient *http.Client
+	lastPrices sync.Map
+	isActive   bool
+	mu         sync.RWMutex
+
+	slugs   sync.Map // map[string]struct{}, the live polled slug set
+	buckets sync.Map // map[string]*slugBucket
 }
 
 // ManifoldMarket represents a market from Manifold API
 type ManifoldMarket struct {
-	ID             string  `json:"id"`
-	Slug           string  `json:"slug"`
-	Question       string  `json:"question"`
-	Probability    float64 `json:"probability"`
-	LastUpdatedTime int64  `json:"lastUpdatedTime"`
-	Volume         float64 `json:"volume"`
-	IsResolved     bool    `json:"isResolved"`
+	ID              string  `json:"id"`
+	Slug            string  `json:"slug"`
+	Question        string  `json:"question"`
+	Probability     float64 `json:"probability"`
+	LastUpdatedTime int64   `json:"lastUpdatedTime"`
+	Volume          float64 `json:"volume"`
+	IsResolved      bool    `json:"isResolved"`
 }
 
-// NewManifoldConnector creates a new Manifold connector
+// NewManifoldConnector creates a new Manifold connector for one
+// SubscriptionGroup. Unlike Kalshi and Polymarket, Manifold has no WS feed
+// to isolate per tenant, so group only determines which slugs this
+// connector polls (via group.Subscriptions) and the GroupID stamped onto
+// its ticks.
 func NewManifoldConnector(
 	cfg *config.Config,
+	group config.SubscriptionGroup,
 	redisClient *redis.Client,
 	metricsReg *metrics.Registry,
 	logger *zap.SugaredLogger,
 ) *ManifoldConnector {
 	return &ManifoldConnector{
-		config: cfg,
-		redis:  redisClient,
+		config:  cfg,
+		group:   group,
+		redis:   redisClient,
 		metrics: metricsReg,
 		logger:  logger.Named("manifold"),
 		httpClient: &http.Client{
@@ -72,19 +125,19 @@ func (m *ManifoldConnector) Start(ctx context.Context) {
 	m.setActive(true)
 	defer m.setActive(false)
 
-	// Collect slugs to poll
-	slugs := m.collectSlugs()
-	if len(slugs) == 0 {
+	for _, slug := range m.collectSlugs() {
+		m.addSlug(slug)
+	}
+
+	if m.slugCount() == 0 {
 		m.logger.Warn("No Manifold markets configured, connector will idle")
-		<-ctx.Done()
-		return
+	} else {
+		m.logger.Infof("Polling %d Manifold markets", m.slugCount())
 	}
 
-	m.logger.Infof("Polling %d Manifold markets", len(slugs))
+	go m.watchControlChannel(ctx)
 
-	// Adaptive polling interval
-	currentInterval := manifoldPollInterval
-	ticker := time.NewTicker(currentInterval)
+	ticker := time.NewTicker(manifoldTickInterval)
 	defer ticker.Stop()
 
 	for {
@@ -94,108 +147,278 @@ func (m *ManifoldConnector) Start(ctx context.Context) {
 			return
 		case <-ticker.C:
 			startTime := time.Now()
-			
-			// Poll all markets
-			volatile := m.pollMarkets(ctx, slugs)
-			
-			// Adjust polling interval based on volatility
-			if volatile {
-				// Switch to fast polling if prices are changing rapidly
-				if currentInterval != manifoldFastInterval {
-					currentInterval = manifoldFastInterval
-					ticker.Reset(currentInterval)
-					m.logger.Info("Switching to fast polling mode")
-				}
-			} else {
-				// Return to normal polling
-				if currentInterval != manifoldPollInterval {
-					currentInterval = manifoldPollInterval
-					ticker.Reset(currentInterval)
-					m.logger.Info("Returning to normal polling mode")
-				}
-			}
-
-			// Record polling duration
+			m.pollDueSlugs(ctx, startTime)
 			m.metrics.RecordProcessingTime(manifoldSource, time.Since(startTime))
 		}
 	}
 }
 
-// collectSlugs collects all Manifold slugs from configuration
+// collectSlugs collects all Manifold slugs from this connector's group
 func (m *ManifoldConnector) collectSlugs() []string {
 	slugs := make([]string, 0)
-	for _, pair := range m.config.Pairs {
-		if pair.Manifold != nil && pair.Manifold.Slug != "" {
-			slugs = append(slugs, pair.Manifold.Slug)
+	for _, sub := range m.group.Subscriptions {
+		if sub.Manifold != nil && sub.Manifold.Slug != "" {
+			slugs = append(slugs, sub.Manifold.Slug)
 		}
 	}
 	return slugs
 }
 
-// pollMarkets polls all markets and returns true if any showed volatility
-func (m *ManifoldConnector) pollMarkets(ctx context.Context, slugs []string) bool {
-	volatile := false
-
-	// Batch API calls if possible (Manifold supports multi-market queries)
-	markets, err := m.fetchMarkets(ctx, slugs)
-	if err != nil {
-		m.logger.Warnf("Failed to fetch markets: %v", err)
-		m.metrics.RecordError(manifoldSource, "fetch_error")
-		return false
+// addSlug adds a slug to the live polled set, starting it in the normal
+// bucket. A no-op if the slug is already tracked.
+func (m *ManifoldConnector) addSlug(slug string) {
+	if _, exists := m.slugs.LoadOrStore(slug, struct{}{}); exists {
+		return
 	}
+	m.buckets.Store(slug, &slugBucket{
+		interval: manifoldNormalInterval,
+		nextPoll: time.Now(),
+	})
+	manifoldPollIntervalSeconds.WithLabelValues(slug).Set(manifoldNormalInterval.Seconds())
+	m.logger.Infof("Added Manifold slug %s to polling set", slug)
+}
 
-	// Process each market
-	for _, market := range markets {
-		if market.IsResolved {
-			continue // Skip resolved markets
+// removeSlug drops a slug from the live polled set and its metrics.
+func (m *ManifoldConnector) removeSlug(slug string) {
+	m.slugs.Delete(slug)
+	m.buckets.Delete(slug)
+	m.lastPrices.Delete(slug)
+	manifoldPollIntervalSeconds.DeleteLabelValues(slug)
+	manifoldVolatilityEWMA.DeleteLabelValues(slug)
+	m.logger.Infof("Removed Manifold slug %s from polling set", slug)
+}
+
+// ApplySubscriptionDelta adds/removes the slugs in delta from the live
+// polling set, so a hot-reloaded config change reaches Manifold the same way
+// it already reaches the Kalshi/Polymarket connectors via
+// Transport.ApplySubscriptionDelta. Manifold has no incremental-subscribe
+// frame to send - addSlug/removeSlug are enough, since polling just reads
+// whatever's in m.slugs on its next tick.
+func (m *ManifoldConnector) ApplySubscriptionDelta(delta config.SubscriptionDelta) error {
+	for _, sub := range delta.Removed {
+		if sub.Manifold != nil && sub.Manifold.Slug != "" {
+			m.removeSlug(sub.Manifold.Slug)
 		}
+	}
+	for _, sub := range delta.Added {
+		if sub.Manifold != nil && sub.Manifold.Slug != "" {
+			m.addSlug(sub.Manifold.Slug)
+		}
+	}
+	for _, sub := range delta.Changed {
+		if sub.Manifold != nil && sub.Manifold.Slug != "" {
+			m.addSlug(sub.Manifold.Slug)
+		}
+	}
+	return nil
+}
+
+func (m *ManifoldConnector) slugCount() int {
+	count := 0
+	m.slugs.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// controlMessage is the JSON shape accepted on manifoldControlChannel and
+// the HTTP control endpoint.
+type controlMessage struct {
+	Action string `json:"action"` // "add" or "remove"
+	Slug   string `json:"slug"`
+}
+
+func (m *ManifoldConnector) applyControlMessage(raw []byte) error {
+	var msg controlMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return fmt.Errorf("invalid control message: %w", err)
+	}
+	if msg.Slug == "" {
+		return fmt.Errorf("control message missing slug")
+	}
+
+	switch msg.Action {
+	case "add":
+		m.addSlug(msg.Slug)
+	case "remove":
+		m.removeSlug(msg.Slug)
+	default:
+		return fmt.Errorf("unknown control action %q", msg.Action)
+	}
+	return nil
+}
+
+// watchControlChannel subscribes to manifold:control and applies add/remove
+// messages to the live polled slug set without requiring a restart.
+func (m *ManifoldConnector) watchControlChannel(ctx context.Context) {
+	sub := m.redis.Subscribe(manifoldControlChannel)
+	defer sub.Close()
 
-		// Check for price change
-		cacheKey := market.ID
-		lastPrice, exists := m.lastPrices.Load(cacheKey)
-		
-		priceChanged := false
-		if exists {
-			change := abs(market.Probability - lastPrice.(float64))
-			if change > manifoldVolatilityThreshold {
-				volatile = true
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
 			}
-			if change > 0.001 { // Only publish if changed by >0.1%
-				priceChanged = true
+			if err := m.applyControlMessage([]byte(msg.Payload)); err != nil {
+				m.logger.Warnf("Failed to apply control message: %v", err)
+				m.metrics.RecordError(manifoldSource, "control_message_error")
 			}
-		} else {
-			priceChanged = true // First time seeing this market
 		}
+	}
+}
 
-		if !priceChanged {
-			m.metrics.RecordDuplicate(manifoldSource)
-			continue
+// ControlHandler returns an HTTP handler operators can mount on the metrics
+// server to add/remove slugs without going through Redis pub/sub, e.g.
+// mux.HandleFunc("/manifold/control", manifoldConn.ControlHandler()).
+func (m *ManifoldConnector) ControlHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
 		}
 
-		m.lastPrices.Store(cacheKey, market.Probability)
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read body: %v", err), http.StatusBadRequest)
+			return
+		}
 
-		// Create tick
-		tick := &models.Tick{
-			Source:          manifoldSource,
-			ContractID:      market.Slug,
-			Price:           market.Probability,
-			TimestampSource: market.LastUpdatedTime,
-			TimestampIngest: time.Now().UnixMilli(),
+		if err := m.applyControlMessage(body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 
-		// Publish to Redis
-		if err := m.redis.PublishTick(tick); err != nil {
-			m.logger.Warnf("Failed to publish tick for %s: %v", market.Slug, err)
-			m.metrics.RecordError(manifoldSource, "redis_error")
-			continue
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ok"}`))
+	}
+}
+
+// pollDueSlugs polls every slug whose bucket interval has elapsed since its
+// last poll, then reschedules each based on its freshly-observed EWMA.
+func (m *ManifoldConnector) pollDueSlugs(ctx context.Context, now time.Time) {
+	due := make([]string, 0)
+	m.buckets.Range(func(key, value interface{}) bool {
+		slug := key.(string)
+		bucket := value.(*slugBucket)
+
+		bucket.mu.Lock()
+		isDue := !now.Before(bucket.nextPoll)
+		bucket.mu.Unlock()
+
+		if isDue {
+			due = append(due, slug)
 		}
+		return true
+	})
+
+	if len(due) == 0 {
+		return
+	}
+
+	markets, err := m.fetchMarkets(ctx, due)
+	if err != nil {
+		m.logger.Warnf("Failed to fetch markets: %v", err)
+		m.metrics.RecordError(manifoldSource, "fetch_error")
+		return
+	}
 
-		// Record metrics
-		m.metrics.RecordMessage(manifoldSource, tick.TimestampSource, true)
-		m.metrics.RecordPrice(manifoldSource, market.Slug, market.Probability)
+	seen := make(map[string]bool, len(markets))
+	for _, market := range markets {
+		seen[market.Slug] = true
+		m.processMarket(&market, now)
 	}
 
-	return volatile
+	// Slugs the API didn't return this round (e.g. resolved/delisted) still
+	// need their next-poll time advanced, or they'd be re-requested every tick.
+	for _, slug := range due {
+		if !seen[slug] {
+			m.rescheduleSlug(slug, now, 0)
+		}
+	}
+}
+
+// processMarket updates the tracked price/volatility for one market, emits a
+// tick on change, and reschedules its bucket.
+func (m *ManifoldConnector) processMarket(market *ManifoldMarket, now time.Time) {
+	if market.IsResolved {
+		m.removeSlug(market.Slug)
+		return
+	}
+
+	cacheKey := market.ID
+	lastPrice, exists := m.lastPrices.Load(cacheKey)
+
+	change := 0.0
+	priceChanged := !exists
+	if exists {
+		change = abs(market.Probability - lastPrice.(float64))
+		if change > 0.001 { // Only publish if changed by >0.1%
+			priceChanged = true
+		}
+	}
+
+	m.rescheduleSlug(market.Slug, now, change)
+
+	if !priceChanged {
+		m.metrics.RecordDuplicate(manifoldSource)
+		return
+	}
+	m.lastPrices.Store(cacheKey, market.Probability)
+
+	tick := &models.Tick{
+		Source:          manifoldSource,
+		ContractID:      market.Slug,
+		GroupID:         m.group.Name,
+		Price:           market.Probability,
+		TimestampSource: market.LastUpdatedTime,
+		TimestampIngest: time.Now().UnixMilli(),
+	}
+
+	if err := m.redis.PublishTick(tick); err != nil {
+		m.logger.Warnf("Failed to publish tick for %s: %v", market.Slug, err)
+		m.metrics.RecordError(manifoldSource, "redis_error")
+		return
+	}
+
+	m.metrics.RecordMessage(manifoldSource, tick.TimestampSource, true)
+	m.metrics.RecordPrice(manifoldSource, market.Slug, market.Probability)
+}
+
+// rescheduleSlug updates a slug's EWMA with the latest observed change,
+// places it in the matching bucket, and sets its next poll time.
+func (m *ManifoldConnector) rescheduleSlug(slug string, now time.Time, change float64) {
+	value, ok := m.buckets.Load(slug)
+	if !ok {
+		return
+	}
+	bucket := value.(*slugBucket)
+
+	bucket.mu.Lock()
+	bucket.ewma = manifoldEWMAAlpha*change + (1-manifoldEWMAAlpha)*bucket.ewma
+	bucket.interval = bucketInterval(bucket.ewma)
+	bucket.nextPoll = now.Add(bucket.interval)
+	ewma, interval := bucket.ewma, bucket.interval
+	bucket.mu.Unlock()
+
+	manifoldVolatilityEWMA.WithLabelValues(slug).Set(ewma)
+	manifoldPollIntervalSeconds.WithLabelValues(slug).Set(interval.Seconds())
+}
+
+// bucketInterval maps an EWMA of volatility to a polling interval.
+func bucketInterval(ewma float64) time.Duration {
+	switch {
+	case ewma > manifoldFastThreshold:
+		return manifoldFastInterval
+	case ewma < manifoldSlowThreshold:
+		return manifoldSlowInterval
+	default:
+		return manifoldNormalInterval
+	}
 }
 
 // fetchMarkets fetches markets from Manifold API
@@ -254,4 +477,4 @@ func abs(x float64) float64 {
 		return -x
 	}
 	return x
-}
\ No newline at end of file
+}
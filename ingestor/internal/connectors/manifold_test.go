@@ -0,0 +1,33 @@
+package connectors
+
+import (
+	"testing"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/config"
+	"go.uber.org/zap"
+)
+
+// TestManifoldConnectorApplySubscriptionDelta confirms a hot-reloaded config
+// change actually reaches the live polled slug set, instead of being
+// silently dropped the way it was before ApplySubscriptionDelta existed.
+func TestManifoldConnectorApplySubscriptionDelta(t *testing.T) {
+	m := &ManifoldConnector{logger: zap.NewNop().Sugar()}
+
+	added := config.MarketSubscription{ID: "m1", Manifold: &config.ManifoldMarketRef{Slug: "will-it-rain"}}
+	if err := m.ApplySubscriptionDelta(config.SubscriptionDelta{Added: []config.MarketSubscription{added}}); err != nil {
+		t.Fatalf("ApplySubscriptionDelta (add): %v", err)
+	}
+	if m.slugCount() != 1 {
+		t.Fatalf("expected 1 polled slug after add, got %d", m.slugCount())
+	}
+	if _, ok := m.slugs.Load("will-it-rain"); !ok {
+		t.Error("expected \"will-it-rain\" to be in the polled slug set")
+	}
+
+	if err := m.ApplySubscriptionDelta(config.SubscriptionDelta{Removed: []config.MarketSubscription{added}}); err != nil {
+		t.Fatalf("ApplySubscriptionDelta (remove): %v", err)
+	}
+	if m.slugCount() != 0 {
+		t.Errorf("expected 0 polled slugs after remove, got %d", m.slugCount())
+	}
+}
@@ -11,150 +11,252 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dragonuber/echoarb/ingestor/internal/auditlog"
 	"github.com/dragonuber/echoarb/ingestor/internal/config"
 	"github.com/dragonuber/echoarb/ingestor/internal/metrics"
 	"github.com/dragonuber/echoarb/ingestor/internal/models"
 	"github.com/dragonuber/echoarb/ingestor/internal/redis"
 	"github.com/dragonuber/echoarb/ingestor/internal/retry"
+	"github.com/dragonuber/echoarb/ingestor/internal/shm"
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
 )
 
 const (
-	polySource         = "POLYMARKET"
-	polyPingInterval   = 30 * time.Second
-	polyPongTimeout    = 60 * time.Second // Must be > pingInterval to handle idle markets
-	polyMarketsURL     = "https://gamma-api.polymarket.com/markets"
-	polyMaxFetchLimit  = 500
-	polySubscribeDelay = 5 * time.Millisecond
+	polySource          = "POLYMARKET"
+	polyPingInterval    = 30 * time.Second
+	polyPongTimeout     = 60 * time.Second // Must be > pingInterval to handle idle markets
+	polyMarketsURL      = "https://gamma-api.polymarket.com/markets"
+	polyMaxFetchLimit   = 500
+	polySubscribeDelay  = 5 * time.Millisecond
+	polyDefaultTickSize = 0.001 // Used when the CLOB /markets response omits tickSize
 )
 
-// PolymarketConnector handles Polymarket WebSocket connection
+// PolymarketConnector wires a PolymarketHandler up to a shared Transport.
 type PolymarketConnector struct {
-	config      *config.Config
-	redis       *redis.Client
-	metrics     *metrics.Registry
-	logger      *zap.SugaredLogger
-	lastPrices  sync.Map
-	isConnected bool
-	mu          sync.RWMutex
+	transport *Transport
+	handler   *PolymarketHandler
 }
 
-// NewPolymarketConnector creates a new Polymarket connector
+// NewPolymarketConnector creates a new Polymarket connector for one
+// SubscriptionGroup. Unlike Kalshi, Polymarket's public feed needs no
+// per-group credentials - group only determines the explicit subscription
+// list (if any) and the GroupID stamped onto its ticks.
 func NewPolymarketConnector(
 	cfg *config.Config,
-	redisClient *redis.Client,
+	group config.SubscriptionGroup,
+	redisClient redis.Publisher,
+	shmWriter *shm.Writer,
+	auditLogger *auditlog.Logger,
 	metricsReg *metrics.Registry,
 	logger *zap.SugaredLogger,
 ) *PolymarketConnector {
-	return &PolymarketConnector{
-		config:  cfg,
-		redis:   redisClient,
-		metrics: metricsReg,
-		logger:  logger.Named("polymarket"),
-	}
+	named := logger.Named("polymarket")
+	handler := NewPolymarketHandler(cfg, group, redisClient, metricsReg, named)
+	transport := NewTransport(polySource, group.Name, TransportConfig{
+		URL:              cfg.PolyWSURL,
+		PingInterval:     polyPingInterval,
+		PongTimeout:      polyPongTimeout,
+		HandshakeTimeout: 30 * time.Second,
+		Compression:      cfg.Compression,
+	}, handler, redisClient, shmWriter, auditLogger, metricsReg, named)
+
+	return &PolymarketConnector{transport: transport, handler: handler}
 }
 
 // Start begins the connection loop with retry
 func (p *PolymarketConnector) Start(ctx context.Context) {
-	p.logger.Info("Starting Polymarket connector")
+	p.transport.Start(ctx, p.retryConfig())
+}
 
-	retryCfg := retry.Config{
-		InitialInterval: p.config.Reconnect.InitialInterval,
-		MaxInterval:     p.config.Reconnect.MaxInterval,
-		MaxRetries:      p.config.Reconnect.MaxRetries,
+// retryConfig builds the retry.Config Start drives the transport with, split
+// out from Start so it can be asserted on directly in tests without actually
+// running the (blocking, networked) reconnect loop.
+func (p *PolymarketConnector) retryConfig() retry.Config {
+	cfg := p.handler.config
+	return retry.Config{
+		InitialInterval: cfg.Reconnect.InitialInterval,
+		MaxInterval:     cfg.Reconnect.MaxInterval,
+		MaxRetries:      cfg.Reconnect.MaxRetries,
 		Multiplier:      2.0,
 		Jitter:          true,
+		JitterStrategy:  cfg.Reconnect.JitterStrategy,
+		Breaker:         cfg.Reconnect.Breaker(),
 	}
+}
 
-	retry.RetryForever(ctx, retryCfg, p.logger, "Polymarket", func() error {
-		return p.connect(ctx)
-	})
+// IsConnected reports whether the transport currently has a live connection.
+func (p *PolymarketConnector) IsConnected() bool {
+	return p.transport.IsConnected()
+}
 
-	p.logger.Info("Polymarket connector stopped")
+// ApplySubscriptionDelta issues incremental subscribe/unsubscribe frames for
+// a hot-reloaded config change, without reconnecting.
+func (p *PolymarketConnector) ApplySubscriptionDelta(delta config.SubscriptionDelta) error {
+	return p.transport.ApplySubscriptionDelta(delta)
 }
 
-// connect establishes WebSocket connection
-func (p *PolymarketConnector) connect(ctx context.Context) error {
-	p.logger.Info("Connecting to Polymarket WebSocket...")
+// ProcessMessage is the exported entrypoint conformance replay drives vector
+// frames through; it runs the exact same path as the live read loop.
+func (p *PolymarketConnector) ProcessMessage(data []byte) error {
+	return p.transport.ProcessAndPublish(data)
+}
 
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 30 * time.Second,
-	}
+// PolymarketHandler implements connectors.Handler for the Polymarket CLOB
+// WebSocket feed. It is a pure parser over frame bytes plus subscribe
+// framing; Transport owns the socket.
+type PolymarketHandler struct {
+	config     *config.Config
+	group      config.SubscriptionGroup
+	redis      redis.ContractMetaPublisher
+	metrics    *metrics.Registry
+	logger     *zap.SugaredLogger
+	lastPrices sync.Map
+	contracts  sync.Map // map[string]*models.ContractInfo, keyed by asset/token ID
+}
 
-	conn, _, err := dialer.Dial(p.config.PolyWSURL, nil)
-	if err != nil {
-		p.metrics.RecordConnection(polySource, false)
-		p.metrics.RecordError(polySource, "connection_error")
-		return fmt.Errorf("failed to connect: %w", err)
+// NewPolymarketHandler creates a Polymarket message handler for one
+// SubscriptionGroup.
+func NewPolymarketHandler(cfg *config.Config, group config.SubscriptionGroup, redisClient redis.ContractMetaPublisher, metricsReg *metrics.Registry, logger *zap.SugaredLogger) *PolymarketHandler {
+	return &PolymarketHandler{
+		config:  cfg,
+		group:   group,
+		redis:   redisClient,
+		metrics: metricsReg,
+		logger:  logger,
 	}
-	defer conn.Close()
+}
 
-	p.metrics.RecordConnection(polySource, true)
-	p.setConnected(true)
-	p.logger.Info("Connected to Polymarket")
+// AuthHeaders returns nil: Polymarket's public market feed needs no auth.
+func (h *PolymarketHandler) AuthHeaders() (http.Header, error) {
+	return nil, nil
+}
 
-	// Set up ping/pong handlers
-	conn.SetPongHandler(func(string) error {
-		conn.SetReadDeadline(time.Now().Add(polyPongTimeout))
+// Subscribe subscribes to every market in h.group.Subscriptions, the same
+// per-tenant isolation Kalshi's Subscribe gets from h.group.Subscriptions.
+// A group with no explicit subscriptions falls back to discovering every
+// currently-active market via the Gamma API, for backward compatibility
+// with configs written before per-group subscription lists existed.
+func (h *PolymarketHandler) Subscribe(conn *websocket.Conn) error {
+	if len(h.group.Subscriptions) > 0 {
+		for _, sub := range h.group.Subscriptions {
+			if err := h.SubscribeMarket(conn, sub); err != nil {
+				return err
+			}
+			time.Sleep(polySubscribeDelay)
+		}
 		return nil
-	})
-
-	// Subscribe to markets
-	if err := p.subscribe(conn); err != nil {
-		return fmt.Errorf("failed to subscribe: %w", err)
 	}
 
-	// Start ping routine
-	pingCtx, pingCancel := context.WithCancel(ctx)
-	defer pingCancel()
-	go p.pingLoop(pingCtx, conn)
-
-	// Read messages
-	return p.readLoop(ctx, conn)
-}
-
-// subscribe subscribes to market updates
-func (p *PolymarketConnector) subscribe(conn *websocket.Conn) error {
-	tokenIDs, err := p.fetchActiveMarkets()
+	tokens, err := h.fetchActiveMarkets()
 	if err != nil {
 		return fmt.Errorf("failed to fetch Polymarket markets via Gamma API: %w", err)
 	}
-	if len(tokenIDs) == 0 {
+	if len(tokens) == 0 {
 		return fmt.Errorf("no Polymarket markets available for subscription")
 	}
 
-	p.logger.Infof("Subscribing to %d Polymarket markets", len(tokenIDs))
+	h.logger.Infof("Subscribing to %d Polymarket markets", len(tokens))
 
-	for _, tokenID := range tokenIDs {
+	for _, token := range tokens {
 		subscribeMsg := map[string]interface{}{
 			"type":   "subscribe",
-			"market": tokenID,
+			"market": token.TokenID,
 		}
 
 		if err := conn.WriteJSON(subscribeMsg); err != nil {
-			return fmt.Errorf("failed to subscribe to %s: %w", tokenID, err)
+			return fmt.Errorf("failed to subscribe to %s: %w", token.TokenID, err)
+		}
+
+		info := &models.ContractInfo{
+			Ticker:        token.TokenID,
+			PriceTickSize: token.TickSize,
+			QuoteCurrency: "USDC",
+		}
+		h.contracts.Store(token.TokenID, info)
+		if err := h.redis.PublishContractMeta(polySource, token.TokenID, info); err != nil {
+			h.logger.Warnf("Failed to publish contract meta for %s: %v", token.TokenID, err)
 		}
 
-		p.logger.Infof("Subscribed to Polymarket market: %s", tokenID)
+		h.logger.Infof("Subscribed to Polymarket market: %s", token.TokenID)
 		time.Sleep(polySubscribeDelay)
 	}
 
 	return nil
 }
 
+// SubscribeMarket subscribes to a single market on an already-open
+// connection, so a config hot-reload can add a market without a reconnect.
+// It is driven by a config.MarketSubscription's explicit token ID; Subscribe
+// calls it for each of the group's subscriptions, and only falls back to
+// Gamma API discovery when the group lists none.
+func (h *PolymarketHandler) SubscribeMarket(conn *websocket.Conn, sub config.MarketSubscription) error {
+	if sub.Polymarket == nil {
+		return nil
+	}
+
+	subscribeMsg := map[string]interface{}{
+		"type":   "subscribe",
+		"market": sub.Polymarket.TokenID,
+	}
+	if err := conn.WriteJSON(subscribeMsg); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", sub.Polymarket.TokenID, err)
+	}
+
+	info := &models.ContractInfo{
+		Ticker:        sub.Polymarket.TokenID,
+		PriceTickSize: polyDefaultTickSize,
+		QuoteCurrency: "USDC",
+	}
+	h.contracts.Store(sub.Polymarket.TokenID, info)
+	if err := h.redis.PublishContractMeta(polySource, sub.Polymarket.TokenID, info); err != nil {
+		h.logger.Warnf("Failed to publish contract meta for %s: %v", sub.Polymarket.TokenID, err)
+	}
+
+	h.logger.Infof("Subscribed to Polymarket market: %s", sub.Polymarket.TokenID)
+	return nil
+}
+
+// UnsubscribeMarket unsubscribes from a single market on an already-open
+// connection, so a config hot-reload can drop a market without a reconnect.
+func (h *PolymarketHandler) UnsubscribeMarket(conn *websocket.Conn, sub config.MarketSubscription) error {
+	if sub.Polymarket == nil {
+		return nil
+	}
+
+	unsubscribeMsg := map[string]interface{}{
+		"type":   "unsubscribe",
+		"market": sub.Polymarket.TokenID,
+	}
+	if err := conn.WriteJSON(unsubscribeMsg); err != nil {
+		return fmt.Errorf("failed to unsubscribe from %s: %w", sub.Polymarket.TokenID, err)
+	}
+
+	h.contracts.Delete(sub.Polymarket.TokenID)
+	h.logger.Infof("Unsubscribed from Polymarket market: %s", sub.Polymarket.TokenID)
+	return nil
+}
+
+// polymarketToken is a token ID paired with the tick size the CLOB reported
+// for it, used to normalize prices into a common probability space.
+type polymarketToken struct {
+	TokenID  string
+	TickSize float64
+}
+
 type polymarketMarket struct {
 	TokenID      string   `json:"tokenId"`
 	TokenIDSnake string   `json:"token_id"`
 	ClobTokenIDs []string `json:"clobTokenIds"`
+	TickSize     float64  `json:"tickSize"`
 	Closed       bool     `json:"closed"`
 }
 
-func (p *PolymarketConnector) fetchActiveMarkets() ([]string, error) {
-	p.logger.Info("Fetching active Polymarket markets via Gamma API")
+func (h *PolymarketHandler) fetchActiveMarkets() ([]polymarketToken, error) {
+	h.logger.Info("Fetching active Polymarket markets via Gamma API")
 	client := &http.Client{Timeout: 15 * time.Second}
 
-	tokenIDs := []string{}
+	tokens := []polymarketToken{}
 	offset := 0
 
 	for {
@@ -190,80 +292,36 @@ func (p *PolymarketConnector) fetchActiveMarkets() ([]string, error) {
 			if market.Closed {
 				continue
 			}
-			if len(market.ClobTokenIDs) > 0 {
-				tokenIDs = append(tokenIDs, market.ClobTokenIDs...)
-				continue
+			tickSize := market.TickSize
+			if tickSize == 0 {
+				tickSize = polyDefaultTickSize
 			}
-			if market.TokenID != "" {
-				tokenIDs = append(tokenIDs, market.TokenID)
-				continue
+
+			var ids []string
+			switch {
+			case len(market.ClobTokenIDs) > 0:
+				ids = market.ClobTokenIDs
+			case market.TokenID != "":
+				ids = []string{market.TokenID}
+			case market.TokenIDSnake != "":
+				ids = []string{market.TokenIDSnake}
 			}
-			if market.TokenIDSnake != "" {
-				tokenIDs = append(tokenIDs, market.TokenIDSnake)
+			for _, id := range ids {
+				tokens = append(tokens, polymarketToken{TokenID: id, TickSize: tickSize})
 			}
 		}
 
 		offset += polyMaxFetchLimit
 	}
 
-	return tokenIDs, nil
+	return tokens, nil
 }
 
-// pingLoop sends periodic pings
-func (p *PolymarketConnector) pingLoop(ctx context.Context, conn *websocket.Conn) {
-	ticker := time.NewTicker(polyPingInterval)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			if err := conn.WriteControl(
-				websocket.PingMessage,
-				[]byte{},
-				time.Now().Add(10*time.Second),
-			); err != nil {
-				p.logger.Warnf("Ping failed: %v", err)
-				return
-			}
-		}
-	}
-}
-
-// readLoop reads messages from WebSocket
-func (p *PolymarketConnector) readLoop(ctx context.Context, conn *websocket.Conn) error {
-	defer p.setConnected(false)
-
-	conn.SetReadDeadline(time.Now().Add(polyPongTimeout))
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		_, message, err := conn.ReadMessage()
-		if err != nil {
-			p.metrics.RecordError(polySource, "read_error")
-			return fmt.Errorf("read error: %w", err)
-		}
-
-		if err := p.processMessage(message); err != nil {
-			p.logger.Warnf("Failed to process message: %v", err)
-			p.metrics.RecordError(polySource, "process_error")
-		}
-	}
-}
-
-// processMessage processes a single message
-func (p *PolymarketConnector) processMessage(data []byte) error {
-	startTime := time.Now()
-
+// ProcessMessage parses a single Polymarket WS frame into zero or more ticks.
+func (h *PolymarketHandler) ProcessMessage(data []byte) ([]*models.Tick, error) {
 	var msg map[string]interface{}
 	if err := json.Unmarshal(data, &msg); err != nil {
-		return fmt.Errorf("json unmarshal error: %w", err)
+		return nil, fmt.Errorf("json unmarshal error: %w", err)
 	}
 
 	// Polymarket message structure varies by type
@@ -271,17 +329,17 @@ func (p *PolymarketConnector) processMessage(data []byte) error {
 
 	switch msgType {
 	case "price_update", "book_update":
-		return p.processPriceUpdate(msg, startTime)
+		return h.processPriceUpdate(msg)
 	case "trade":
-		return p.processTradeUpdate(msg, startTime)
+		return h.processTradeUpdate(msg)
 	default:
 		// Ignore other message types
-		return nil
+		return nil, nil
 	}
 }
 
 // processPriceUpdate handles price update messages
-func (p *PolymarketConnector) processPriceUpdate(msg map[string]interface{}, startTime time.Time) error {
+func (h *PolymarketHandler) processPriceUpdate(msg map[string]interface{}) ([]*models.Tick, error) {
 	// Extract fields (structure varies, this is simplified)
 	assetID, _ := msg["asset_id"].(string)
 	if assetID == "" {
@@ -290,7 +348,7 @@ func (p *PolymarketConnector) processPriceUpdate(msg map[string]interface{}, sta
 	}
 
 	if assetID == "" {
-		return fmt.Errorf("missing asset_id")
+		return nil, fmt.Errorf("missing asset_id")
 	}
 
 	// Price is usually in the range 0-1
@@ -309,52 +367,44 @@ func (p *PolymarketConnector) processPriceUpdate(msg map[string]interface{}, sta
 	}
 
 	// Check for duplicate
-	cacheKey := assetID
-	if lastPrice, ok := p.lastPrices.Load(cacheKey); ok {
+	if lastPrice, ok := h.lastPrices.Load(assetID); ok {
 		if lastPrice.(float64) == price {
-			p.metrics.RecordDuplicate(polySource)
-			return nil
+			h.metrics.RecordDuplicate(polySource)
+			return nil, nil
 		}
 	}
-	p.lastPrices.Store(cacheKey, price)
+	h.lastPrices.Store(assetID, price)
+
+	tickSize := polyDefaultTickSize
+	if info, ok := h.contracts.Load(assetID); ok {
+		tickSize = info.(*models.ContractInfo).PriceTickSize
+	}
 
-	// Create tick
 	tick := &models.Tick{
 		Source:          polySource,
 		ContractID:      assetID,
 		Price:           price,
+		MidPrice:        price,
+		TickSize:        tickSize,
 		TimestampSource: int64(timestamp),
 		TimestampIngest: time.Now().UnixMilli(),
 	}
 
-	// Publish to Redis
-	if err := p.redis.PublishTick(tick); err != nil {
-		p.metrics.RecordError(polySource, "redis_error")
-		return fmt.Errorf("failed to publish: %w", err)
-	}
-
-	// Record metrics
-	p.metrics.RecordMessage(polySource, tick.TimestampSource, true)
-	p.metrics.RecordProcessingTime(polySource, time.Since(startTime))
-	p.metrics.RecordPrice(polySource, assetID, price)
-
-	return nil
+	return []*models.Tick{tick}, nil
 }
 
 // processTradeUpdate handles trade messages
-func (p *PolymarketConnector) processTradeUpdate(msg map[string]interface{}, startTime time.Time) error {
-	// Extract trade details
+func (h *PolymarketHandler) processTradeUpdate(msg map[string]interface{}) ([]*models.Tick, error) {
 	assetID, _ := msg["asset_id"].(string)
 	price, _ := msg["price"].(float64)
 
 	if assetID == "" || price == 0 {
-		return nil // Ignore incomplete trades
+		return nil, nil // Ignore incomplete trades
 	}
 
 	// Update last price based on trade
-	p.lastPrices.Store(assetID, price)
+	h.lastPrices.Store(assetID, price)
 
-	// Create tick from trade price
 	tick := &models.Tick{
 		Source:          polySource,
 		ContractID:      assetID,
@@ -363,27 +413,5 @@ func (p *PolymarketConnector) processTradeUpdate(msg map[string]interface{}, sta
 		TimestampIngest: time.Now().UnixMilli(),
 	}
 
-	if err := p.redis.PublishTick(tick); err != nil {
-		return fmt.Errorf("failed to publish: %w", err)
-	}
-
-	p.metrics.RecordMessage(polySource, tick.TimestampSource, true)
-	p.metrics.RecordProcessingTime(polySource, time.Since(startTime))
-
-	return nil
-}
-
-// Helper methods
-func (p *PolymarketConnector) setConnected(connected bool) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-	p.isConnected = connected
-	p.metrics.SetConnectionActive(polySource, connected)
-	p.metrics.SetHealthStatus("polymarket", connected)
-}
-
-func (p *PolymarketConnector) IsConnected() bool {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	return p.isConnected
+	return []*models.Tick{tick}, nil
 }
@@ -0,0 +1,43 @@
+package connectors
+
+import (
+	"testing"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/config"
+	"github.com/dragonuber/echoarb/ingestor/internal/conformance"
+	"github.com/dragonuber/echoarb/ingestor/internal/metrics"
+	"go.uber.org/zap"
+)
+
+// TestPolymarketHandlerProcessMessage exercises
+// PolymarketHandler.ProcessMessage directly, with no socket or Redis
+// involved - the point of splitting parsing out of Transport in the first
+// place.
+func TestPolymarketHandlerProcessMessage(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	handler := NewPolymarketHandler(&config.Config{}, config.SubscriptionGroup{}, conformance.NewFakeRedis(), metrics.NewRegistry(), logger)
+
+	ticks, err := handler.ProcessMessage([]byte(`{"type":"price_update","asset_id":"0xabc123","price":0.41,"timestamp":1735603200000}`))
+	if err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	if len(ticks) != 1 {
+		t.Fatalf("expected 1 tick, got %d", len(ticks))
+	}
+	if ticks[0].Price != 0.41 {
+		t.Errorf("unexpected price %v", ticks[0].Price)
+	}
+}
+
+func TestPolymarketHandlerProcessMessageIgnoresUnknownEvent(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	handler := NewPolymarketHandler(&config.Config{}, config.SubscriptionGroup{}, conformance.NewFakeRedis(), metrics.NewRegistry(), logger)
+
+	ticks, err := handler.ProcessMessage([]byte(`{"type":"unknown_event","asset_id":"0xabc123","price":0.99,"timestamp":1735603207000}`))
+	if err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	if ticks != nil {
+		t.Errorf("expected no ticks for an unknown event type, got %d", len(ticks))
+	}
+}
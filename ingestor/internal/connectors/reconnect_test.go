@@ -0,0 +1,74 @@
+package connectors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/config"
+	"github.com/dragonuber/echoarb/ingestor/internal/retry"
+)
+
+// reconnectTestConfig builds a Config with a distinctive ReconnectConfig so
+// these tests can tell its values actually reached the connector's
+// retry.Config, instead of asserting against zero values both sides share.
+func reconnectTestConfig() *config.Config {
+	return &config.Config{
+		Reconnect: config.ReconnectConfig{
+			InitialInterval:  time.Second,
+			MaxInterval:      time.Minute,
+			MaxRetries:       7,
+			JitterStrategy:   retry.JitterDecorrelated,
+			BreakerThreshold: 3,
+			BreakerTimeout:   10 * time.Second,
+		},
+	}
+}
+
+// TestKalshiConnectorRetryConfigUsesReconnectSettings confirms
+// KalshiConnector.Start drives the transport's reconnect loop with the
+// JitterStrategy and a CircuitBreaker built from cfg.Reconnect, instead of
+// the legacy hardcoded Jitter-only retry.Config.
+func TestKalshiConnectorRetryConfigUsesReconnectSettings(t *testing.T) {
+	cfg := reconnectTestConfig()
+	conn := &KalshiConnector{handler: &KalshiHandler{config: cfg}}
+
+	rc := conn.retryConfig()
+	if rc.JitterStrategy != retry.JitterDecorrelated {
+		t.Errorf("expected JitterStrategy %q, got %q", retry.JitterDecorrelated, rc.JitterStrategy)
+	}
+	if rc.MaxRetries != cfg.Reconnect.MaxRetries {
+		t.Errorf("expected MaxRetries %d, got %d", cfg.Reconnect.MaxRetries, rc.MaxRetries)
+	}
+	if rc.Breaker == nil {
+		t.Fatal("expected a non-nil Breaker for BreakerThreshold > 0")
+	}
+	if !rc.Breaker.Allow() {
+		t.Error("a freshly-built Breaker should start closed and allow attempts")
+	}
+}
+
+// TestPolymarketConnectorRetryConfigUsesReconnectSettings is the Polymarket
+// equivalent of TestKalshiConnectorRetryConfigUsesReconnectSettings.
+func TestPolymarketConnectorRetryConfigUsesReconnectSettings(t *testing.T) {
+	cfg := reconnectTestConfig()
+	conn := &PolymarketConnector{handler: &PolymarketHandler{config: cfg}}
+
+	rc := conn.retryConfig()
+	if rc.JitterStrategy != retry.JitterDecorrelated {
+		t.Errorf("expected JitterStrategy %q, got %q", retry.JitterDecorrelated, rc.JitterStrategy)
+	}
+	if rc.Breaker == nil {
+		t.Fatal("expected a non-nil Breaker for BreakerThreshold > 0")
+	}
+}
+
+// TestReconnectConfigBreakerDisabledByDefault confirms a zero-value
+// ReconnectConfig (e.g. a config.Config{} built without Load, as many tests
+// in this package do) yields a nil Breaker instead of an enabled one with a
+// zero threshold, which would trip open on the very first failure.
+func TestReconnectConfigBreakerDisabledByDefault(t *testing.T) {
+	var rc config.ReconnectConfig
+	if b := rc.Breaker(); b != nil {
+		t.Errorf("expected a nil Breaker for BreakerThreshold 0, got %+v", b)
+	}
+}
@@ -0,0 +1,364 @@
+// internal/connectors/transport.go
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/auditlog"
+	"github.com/dragonuber/echoarb/ingestor/internal/config"
+	"github.com/dragonuber/echoarb/ingestor/internal/metrics"
+	"github.com/dragonuber/echoarb/ingestor/internal/models"
+	"github.com/dragonuber/echoarb/ingestor/internal/redis"
+	"github.com/dragonuber/echoarb/ingestor/internal/retry"
+	"github.com/dragonuber/echoarb/ingestor/internal/shm"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// Handler implements exchange-specific framing on top of a shared Transport.
+// ProcessMessage is a pure function over raw frame bytes (no socket, no
+// Redis) so it can be unit tested or replayed against a vector corpus in
+// isolation; Transport is the only thing that calls it from a live read loop.
+type Handler interface {
+	// Subscribe writes whatever subscribe frames the exchange requires.
+	Subscribe(conn *websocket.Conn) error
+	// ProcessMessage parses one raw WS frame into zero or more ticks.
+	ProcessMessage(data []byte) ([]*models.Tick, error)
+	// AuthHeaders returns headers for the WS handshake, or nil if none are needed.
+	AuthHeaders() (http.Header, error)
+}
+
+// IncrementalHandler is implemented by Handlers that can subscribe to or
+// unsubscribe from a single market on an already-open connection. Transport
+// uses it to apply a config.SubscriptionDelta from a hot-reloaded config
+// file without tearing down and reconnecting the socket. For a Handler that
+// doesn't implement it, subscription deltas are logged and dropped.
+type IncrementalHandler interface {
+	SubscribeMarket(conn *websocket.Conn, sub config.MarketSubscription) error
+	UnsubscribeMarket(conn *websocket.Conn, sub config.MarketSubscription) error
+}
+
+// Resyncer is implemented by Handlers that track per-market state (e.g.
+// orderbook.Store) and can detect that it fell out of sync - a sequence gap
+// - with the venue. Transport drains PendingResyncs after every processed
+// message and, for a Handler that's also an IncrementalHandler, reissues an
+// unsubscribe/subscribe cycle for each one, the same dance
+// ApplySubscriptionDelta does for a config.SubscriptionDelta.Changed entry.
+// That forces the venue to push a fresh snapshot instead of leaving the book
+// invalid until the next reconnect.
+type Resyncer interface {
+	PendingResyncs() []config.MarketSubscription
+}
+
+// TransportConfig holds the dial/ping/reconnect settings every exchange
+// connector needs; only the values differ per venue.
+type TransportConfig struct {
+	URL              string
+	PingInterval     time.Duration
+	PongTimeout      time.Duration // must be > PingInterval to tolerate idle markets
+	HandshakeTimeout time.Duration
+
+	// Compression selects how inbound frames are decompressed before being
+	// handed to the Handler: CompressionNone (default), CompressionGzip (the
+	// whole frame payload is gzipped), or CompressionDeflate (negotiate
+	// permessage-deflate on the dialer; gorilla/websocket inflates frames
+	// transparently once negotiated, so no extra read-loop step is needed).
+	Compression string
+}
+
+// Transport owns the WebSocket lifecycle - dial, auth, ping/pong, read loop,
+// reconnect - that used to be duplicated ~200 lines per connector. It knows
+// nothing about message schemas; that's entirely the Handler's job.
+type Transport struct {
+	source    string
+	groupID   string
+	cfg       TransportConfig
+	handler   Handler
+	redis     redis.TickPublisher
+	shmWriter *shm.Writer
+	auditLog  *auditlog.Logger
+	metrics   *metrics.Registry
+	logger    *zap.SugaredLogger
+
+	mu          sync.RWMutex
+	isConnected bool
+	conn        *websocket.Conn
+}
+
+// NewTransport creates a Transport for one exchange Handler. auditLogger may
+// be a disabled *auditlog.Logger (from auditlog.New with Enabled: false); it
+// must not be nil. groupID is stamped onto every tick ProcessAndPublish
+// produces, so a downstream consumer can tell which SubscriptionGroup a
+// tick came from even after it's been published into a shared Redis.
+// shmWriter may be nil (the shared-memory ring buffer is disabled or this
+// Transport is under test), in which case ProcessAndPublish only publishes
+// to Redis.
+func NewTransport(
+	source string,
+	groupID string,
+	cfg TransportConfig,
+	handler Handler,
+	redisClient redis.TickPublisher,
+	shmWriter *shm.Writer,
+	auditLogger *auditlog.Logger,
+	metricsReg *metrics.Registry,
+	logger *zap.SugaredLogger,
+) *Transport {
+	return &Transport{
+		source:    source,
+		groupID:   groupID,
+		cfg:       cfg,
+		handler:   handler,
+		redis:     redisClient,
+		shmWriter: shmWriter,
+		auditLog:  auditLogger,
+		metrics:   metricsReg,
+		logger:    logger,
+	}
+}
+
+// Start begins the connection loop with retry.
+func (t *Transport) Start(ctx context.Context, retryCfg retry.Config) {
+	t.logger.Info("Starting connector")
+
+	retry.RetryForever(ctx, retryCfg, t.logger, t.source, func() error {
+		return t.connect(ctx)
+	})
+
+	t.logger.Info("Connector stopped")
+}
+
+// connect establishes a single WebSocket connection and runs it until it errors.
+func (t *Transport) connect(ctx context.Context) error {
+	t.logger.Info("Connecting...")
+
+	headers, err := t.handler.AuthHeaders()
+	if err != nil {
+		t.metrics.RecordError(t.source, "auth_error")
+		return fmt.Errorf("failed to get auth headers: %w", err)
+	}
+
+	dialer := websocket.Dialer{
+		HandshakeTimeout:  t.cfg.HandshakeTimeout,
+		EnableCompression: t.cfg.Compression == CompressionDeflate,
+	}
+
+	conn, _, err := dialer.Dial(t.cfg.URL, headers)
+	if err != nil {
+		t.metrics.RecordConnection(t.source, false)
+		t.metrics.RecordError(t.source, "connection_error")
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	t.metrics.RecordConnection(t.source, true)
+	t.setConnected(true, conn)
+	t.logger.Info("Connected")
+
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(t.cfg.PongTimeout))
+		return nil
+	})
+
+	if err := t.handler.Subscribe(conn); err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	pingCtx, pingCancel := context.WithCancel(ctx)
+	defer pingCancel()
+	go t.pingLoop(pingCtx, conn)
+
+	return t.readLoop(ctx, conn)
+}
+
+// pingLoop sends periodic pings to keep the connection alive.
+func (t *Transport) pingLoop(ctx context.Context, conn *websocket.Conn) {
+	ticker := time.NewTicker(t.cfg.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(
+				websocket.PingMessage,
+				[]byte{},
+				time.Now().Add(10*time.Second),
+			); err != nil {
+				t.logger.Warnf("Ping failed: %v", err)
+				return
+			}
+		}
+	}
+}
+
+// readLoop reads messages from the WebSocket and hands each to the handler.
+func (t *Transport) readLoop(ctx context.Context, conn *websocket.Conn) error {
+	defer t.setConnected(false, nil)
+
+	conn.SetReadDeadline(time.Now().Add(t.cfg.PongTimeout))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			t.metrics.RecordError(t.source, "read_error")
+			return fmt.Errorf("read error: %w", err)
+		}
+
+		if t.cfg.Compression == CompressionGzip {
+			message, err = GzipDecompress(message)
+			if err != nil {
+				t.logger.Warnf("Failed to decompress message: %v", err)
+				t.metrics.RecordError(t.source, "decompress_error")
+				continue
+			}
+		}
+
+		if err := t.ProcessAndPublish(message); err != nil {
+			t.logger.Warnf("Failed to process message: %v", err)
+			t.metrics.RecordError(t.source, "process_error")
+			// Continue reading despite processing errors
+		}
+
+		t.resyncPending(conn)
+	}
+}
+
+// resyncPending asks the handler (if it's a Resyncer) which markets fell out
+// of sync processing the last message, and - if the handler is also an
+// IncrementalHandler - reissues an unsubscribe/subscribe cycle for each one
+// on conn so the venue pushes a fresh snapshot. A handler that implements
+// neither interface has nothing to resync; this is a no-op for it.
+func (t *Transport) resyncPending(conn *websocket.Conn) {
+	resyncer, ok := t.handler.(Resyncer)
+	if !ok {
+		return
+	}
+	incremental, ok := t.handler.(IncrementalHandler)
+	if !ok {
+		return
+	}
+
+	for _, sub := range resyncer.PendingResyncs() {
+		t.logger.Warnf("Resyncing %s after sequence gap", sub.ID)
+		if err := incremental.UnsubscribeMarket(conn, sub); err != nil {
+			t.logger.Warnf("Failed to unsubscribe %s for resync: %v", sub.ID, err)
+		}
+		if err := incremental.SubscribeMarket(conn, sub); err != nil {
+			t.logger.Warnf("Failed to resubscribe %s for resync: %v", sub.ID, err)
+		}
+	}
+}
+
+// ProcessAndPublish runs a raw frame through the handler and publishes every
+// resulting tick. It is the single path shared by the live read loop and the
+// exported ProcessMessage each connector exposes for conformance replay.
+func (t *Transport) ProcessAndPublish(data []byte) error {
+	startTime := time.Now()
+
+	ticks, err := t.handler.ProcessMessage(data)
+	if err != nil {
+		return err
+	}
+
+	for _, tick := range ticks {
+		tick.GroupID = t.groupID
+
+		if err := t.redis.PublishTick(tick); err != nil {
+			t.metrics.RecordError(t.source, "redis_error")
+			return fmt.Errorf("failed to publish: %w", err)
+		}
+
+		if t.shmWriter != nil {
+			if _, err := t.shmWriter.Write(tick); err != nil {
+				t.logger.Warnf("Failed to write tick to shm ring: %v", err)
+				t.metrics.RecordError(t.source, "shm_write_error")
+			}
+		}
+
+		if err := t.auditLog.WriteTick(tick); err != nil {
+			t.logger.Warnf("Failed to write audit log entry: %v", err)
+			t.metrics.RecordError(t.source, "audit_log_error")
+		}
+
+		t.metrics.RecordMessage(t.source, tick.TimestampSource, true)
+		t.metrics.RecordPrice(t.source, tick.ContractID, tick.Price)
+	}
+
+	if len(ticks) > 0 {
+		t.metrics.RecordProcessingTime(t.source, time.Since(startTime))
+	}
+
+	return nil
+}
+
+func (t *Transport) setConnected(connected bool, conn *websocket.Conn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.isConnected = connected
+	t.conn = conn
+	t.metrics.SetConnectionActive(t.source, connected)
+}
+
+// IsConnected reports whether the transport currently has a live connection.
+func (t *Transport) IsConnected() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.isConnected
+}
+
+// ApplySubscriptionDelta issues incremental subscribe/unsubscribe frames for
+// delta on the current connection, if the Handler supports it and a
+// connection is currently live. Unsubscribes are sent before subscribes, so
+// a market that moved between subscription IDs in the same reload doesn't
+// briefly double-subscribe. If the Handler doesn't implement
+// IncrementalHandler, or there's no live connection right now, the delta is
+// logged and dropped. A delta is never recorded in the Handler's own
+// subscription list: Subscribe on a later reconnect subscribes the list the
+// Handler was built with, not the hot-reloaded one.
+func (t *Transport) ApplySubscriptionDelta(delta config.SubscriptionDelta) error {
+	incremental, ok := t.handler.(IncrementalHandler)
+	if !ok {
+		t.logger.Warnf("Handler does not support incremental subscriptions; dropping subscription delta")
+		return nil
+	}
+
+	t.mu.RLock()
+	conn := t.conn
+	t.mu.RUnlock()
+	if conn == nil {
+		t.logger.Warnf("No live connection; dropping subscription delta")
+		return nil
+	}
+
+	for _, sub := range delta.Removed {
+		if err := incremental.UnsubscribeMarket(conn, sub); err != nil {
+			return fmt.Errorf("unsubscribe %s: %w", sub.ID, err)
+		}
+	}
+	for _, sub := range delta.Added {
+		if err := incremental.SubscribeMarket(conn, sub); err != nil {
+			return fmt.Errorf("subscribe %s: %w", sub.ID, err)
+		}
+	}
+	for _, sub := range delta.Changed {
+		if err := incremental.UnsubscribeMarket(conn, sub); err != nil {
+			return fmt.Errorf("resubscribe (unsubscribe step) %s: %w", sub.ID, err)
+		}
+		if err := incremental.SubscribeMarket(conn, sub); err != nil {
+			return fmt.Errorf("resubscribe (subscribe step) %s: %w", sub.ID, err)
+		}
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 type Tick struct {
 	Source          string  `json:"source" msgpack:"source"`
 	ContractID      string  `json:"contract_id" msgpack:"contract_id"`
+	GroupID         string  `json:"group_id,omitempty" msgpack:"group_id,omitempty"` // SubscriptionGroup.Name this tick's feed belongs to
 	Price           float64 `json:"price" msgpack:"price"`
 	TimestampSource int64   `json:"ts_source" msgpack:"ts_source"` // Exchange timestamp (ms)
 	TimestampIngest int64   `json:"ts_ingest" msgpack:"ts_ingest"` // Our receive time (ms)
@@ -40,6 +41,23 @@ type Tick struct {
 	MarketID   string `json:"market_id,omitempty" msgpack:"market_id,omitempty"`     // Market UUID (Kalshi) or Condition ID (Polymarket)
 	MarketName string `json:"market_name,omitempty" msgpack:"market_name,omitempty"` // Human-readable name
 	EventType  string `json:"event_type,omitempty" msgpack:"event_type,omitempty"`   // Message type that generated this tick
+
+	// Normalized quote fields, derived using the contract's ContractInfo
+	// instead of a hardcoded conversion factor
+	BestBid  float64 `json:"best_bid,omitempty" msgpack:"best_bid,omitempty"`   // Best bid, normalized to 0-1
+	BestAsk  float64 `json:"best_ask,omitempty" msgpack:"best_ask,omitempty"`   // Best ask, normalized to 0-1
+	MidPrice float64 `json:"mid_price,omitempty" msgpack:"mid_price,omitempty"` // (BestBid+BestAsk)/2, normalized to 0-1
+	TickSize float64 `json:"tick_size,omitempty" msgpack:"tick_size,omitempty"` // Contract's price tick size used for normalization
+}
+
+// ContractInfo carries venue-specific contract metadata used to normalize
+// raw exchange prices into a common 0-1 probability space, and to compute
+// min-profitable spreads downstream. Populated at subscribe time.
+type ContractInfo struct {
+	Ticker        string  `json:"ticker"`
+	PriceTickSize float64 `json:"price_tick_size"` // Smallest price increment, in probability space (e.g. 0.01 for Kalshi cents)
+	QuoteCurrency string  `json:"quote_currency"`
+	Underlying    string  `json:"underlying,omitempty"`
 }
 
 // Validate checks if tick data is valid
@@ -83,12 +101,14 @@ func (e *TickError) Error() string {
 
 // OrderbookSnapshot represents a full orderbook state
 type OrderbookSnapshot struct {
-	Ticker      string         `json:"ticker"`
-	Timestamp   int64          `json:"timestamp"`
-	YesBids     []PriceLevel   `json:"yes_bids"`
-	YesAsks     []PriceLevel   `json:"yes_asks"`
-	NoBids      []PriceLevel   `json:"no_bids"`
-	NoAsks      []PriceLevel   `json:"no_asks"`
+	Ticker       string       `json:"ticker"`
+	Timestamp    int64        `json:"timestamp"`
+	LastUpdateID int64        `json:"last_update_id"`      // Sequence number this snapshot reflects; deltas with seq <= this are already applied
+	Checksum     int64        `json:"checksum,omitempty"`  // Optional venue-supplied checksum over the book, for validation
+	YesBids      []PriceLevel `json:"yes_bids"`
+	YesAsks      []PriceLevel `json:"yes_asks"`
+	NoBids       []PriceLevel `json:"no_bids"`
+	NoAsks       []PriceLevel `json:"no_asks"`
 }
 
 // PriceLevel represents a single level in the orderbook
@@ -101,6 +121,7 @@ type PriceLevel struct {
 type OrderbookDelta struct {
 	Ticker    string       `json:"ticker"`
 	Timestamp int64        `json:"timestamp"`
+	Seq       int64        `json:"seq"` // Monotonic per-ticker sequence number, used to detect gaps and buffer pre-snapshot deltas
 	Updates   []BookUpdate `json:"updates"`
 }
 
@@ -0,0 +1,246 @@
+// Package orderbook maintains live per-ticker order books from venue
+// snapshot+delta WebSocket channels, following the pattern exchanges like
+// Kalshi and Binance use for depth streams: buffer deltas that arrive before
+// the initial snapshot, apply the snapshot, then replay only the buffered
+// deltas newer than the snapshot's last-update-id. A sequence gap after that
+// point means a delta was missed, so the book is marked invalid until the
+// next snapshot resyncs it.
+package orderbook
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/models"
+)
+
+// Book is one ticker's live reconstructed orderbook, rebuilt from an initial
+// snapshot plus a stream of incremental deltas.
+type Book struct {
+	mu     sync.RWMutex
+	ticker string
+
+	hasSnapshot bool
+	valid       bool
+	lastSeq     int64
+
+	yesBids map[int]int // price (cents) -> quantity
+	yesAsks map[int]int
+	noBids  map[int]int
+	noAsks  map[int]int
+
+	buffer []*models.OrderbookDelta // deltas received before the first snapshot
+}
+
+// NewBook creates an empty, not-yet-valid book for a ticker. It becomes valid
+// once the first snapshot is applied.
+func NewBook(ticker string) *Book {
+	return &Book{
+		ticker:  ticker,
+		yesBids: make(map[int]int),
+		yesAsks: make(map[int]int),
+		noBids:  make(map[int]int),
+		noAsks:  make(map[int]int),
+	}
+}
+
+// ApplySnapshot resets the book to a full snapshot, then replays any deltas
+// that were buffered while waiting for it whose Seq is newer than the
+// snapshot's LastUpdateID.
+func (b *Book) ApplySnapshot(snap *models.OrderbookSnapshot) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.yesBids = levelsToMap(snap.YesBids)
+	b.yesAsks = levelsToMap(snap.YesAsks)
+	b.noBids = levelsToMap(snap.NoBids)
+	b.noAsks = levelsToMap(snap.NoAsks)
+	b.lastSeq = snap.LastUpdateID
+	b.hasSnapshot = true
+	b.valid = true
+
+	if snap.Checksum != 0 {
+		if got := b.checksumLocked(); got != snap.Checksum {
+			b.valid = false
+			return fmt.Errorf("orderbook checksum mismatch for %s: snapshot says %d, computed %d", b.ticker, snap.Checksum, got)
+		}
+	}
+
+	buffered := b.buffer
+	b.buffer = nil
+	sort.Slice(buffered, func(i, j int) bool { return buffered[i].Seq < buffered[j].Seq })
+
+	for _, delta := range buffered {
+		if delta.Seq <= b.lastSeq {
+			continue // already reflected in the snapshot
+		}
+		if delta.Seq != b.lastSeq+1 {
+			b.valid = false
+			return fmt.Errorf("sequence gap replaying buffered deltas for %s: have %d, next %d", b.ticker, b.lastSeq, delta.Seq)
+		}
+		b.applyDeltaLocked(delta)
+	}
+
+	return nil
+}
+
+// ApplyDelta applies one incremental update and returns a tick for the
+// resulting best bid/ask (none if the YES side is empty), whether or not the
+// update changed the top of book. If no
+// snapshot has been applied yet, the delta is buffered and replayed once
+// ApplySnapshot runs. A detected sequence gap marks the book invalid and
+// returns an error; the caller should request a fresh snapshot.
+func (b *Book) ApplyDelta(delta *models.OrderbookDelta) ([]*models.Tick, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.hasSnapshot {
+		b.buffer = append(b.buffer, delta)
+		return nil, nil
+	}
+
+	if delta.Seq <= b.lastSeq {
+		return nil, nil // stale or duplicate delta
+	}
+	if delta.Seq != b.lastSeq+1 {
+		b.valid = false
+		return nil, fmt.Errorf("sequence gap on %s: have %d, got %d", b.ticker, b.lastSeq, delta.Seq)
+	}
+
+	b.applyDeltaLocked(delta)
+	return b.deriveTicksLocked(delta.Timestamp), nil
+}
+
+func (b *Book) applyDeltaLocked(delta *models.OrderbookDelta) {
+	for _, u := range delta.Updates {
+		levels := b.levelsForLocked(u.Side, u.Type)
+		if levels == nil {
+			continue // unknown side/type, ignore rather than corrupt the book
+		}
+		if u.Quantity == 0 {
+			delete(levels, u.Price)
+		} else {
+			levels[u.Price] = u.Quantity
+		}
+	}
+	b.lastSeq = delta.Seq
+}
+
+func (b *Book) levelsForLocked(side, typ string) map[int]int {
+	switch {
+	case side == "yes" && typ == "bid":
+		return b.yesBids
+	case side == "yes" && typ == "ask":
+		return b.yesAsks
+	case side == "no" && typ == "bid":
+		return b.noBids
+	case side == "no" && typ == "ask":
+		return b.noAsks
+	default:
+		return nil
+	}
+}
+
+// deriveTicksLocked emits a tick for the current best-bid/ask/mid on the YES
+// side, the same quantity downstream arb consumers already key off of.
+func (b *Book) deriveTicksLocked(sourceTS int64) []*models.Tick {
+	bestBid, haveBid := bestLevel(b.yesBids, true)
+	bestAsk, haveAsk := bestLevel(b.yesAsks, false)
+	if !haveBid && !haveAsk {
+		return nil
+	}
+
+	bid := float64(bestBid) / 100.0
+	ask := float64(bestAsk) / 100.0
+	mid := (bid + ask) / 2.0
+
+	tick := &models.Tick{
+		Source:          "ORDERBOOK",
+		ContractID:      b.ticker,
+		Price:           mid,
+		BestBid:         bid,
+		BestAsk:         ask,
+		MidPrice:        mid,
+		TickSize:        0.01,
+		TimestampSource: sourceTS,
+		TimestampIngest: time.Now().UnixMilli(),
+		EventType:       "orderbook_book_update",
+	}
+	return []*models.Tick{tick}
+}
+
+func bestLevel(levels map[int]int, highest bool) (int, bool) {
+	best := 0
+	found := false
+	for price := range levels {
+		if !found || (highest && price > best) || (!highest && price < best) {
+			best = price
+			found = true
+		}
+	}
+	return best, found
+}
+
+// GetSnapshot returns the book's current full state.
+func (b *Book) GetSnapshot() *models.OrderbookSnapshot {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return &models.OrderbookSnapshot{
+		Ticker:       b.ticker,
+		Timestamp:    time.Now().UnixMilli(),
+		LastUpdateID: b.lastSeq,
+		Checksum:     b.checksumLocked(),
+		YesBids:      mapToLevels(b.yesBids),
+		YesAsks:      mapToLevels(b.yesAsks),
+		NoBids:       mapToLevels(b.noBids),
+		NoAsks:       mapToLevels(b.noAsks),
+	}
+}
+
+// IsValid reports whether the book is in a consistent state. It is false
+// after a detected sequence gap, until the next snapshot resyncs it.
+func (b *Book) IsValid() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.valid
+}
+
+// checksumLocked is a simple order-independent checksum over every level, so
+// two books built from the same levels in different delta order agree. Not
+// cryptographic - just enough to catch a book that's drifted from the venue.
+func (b *Book) checksumLocked() int64 {
+	var sum int64
+	add := func(levels map[int]int) {
+		for price, qty := range levels {
+			sum += int64(price)*int64(qty) + int64(price)
+		}
+	}
+	add(b.yesBids)
+	add(b.yesAsks)
+	add(b.noBids)
+	add(b.noAsks)
+	return sum
+}
+
+func levelsToMap(levels []models.PriceLevel) map[int]int {
+	m := make(map[int]int, len(levels))
+	for _, l := range levels {
+		if l.Quantity == 0 {
+			continue
+		}
+		m[l.Price] = l.Quantity
+	}
+	return m
+}
+
+func mapToLevels(m map[int]int) []models.PriceLevel {
+	levels := make([]models.PriceLevel, 0, len(m))
+	for price, qty := range m {
+		levels = append(levels, models.PriceLevel{Price: price, Quantity: qty})
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Price < levels[j].Price })
+	return levels
+}
@@ -0,0 +1,89 @@
+package orderbook
+
+import (
+	"sync"
+
+	"github.com/dragonuber/echoarb/ingestor/internal/models"
+	"github.com/dragonuber/echoarb/ingestor/internal/redis"
+	"go.uber.org/zap"
+)
+
+// Store keeps one Book per ticker and publishes full snapshots to Redis
+// whenever one is applied or replaced. Connector handlers own the
+// snapshot/delta WS framing; Store just owns book lifecycle across tickers.
+type Store struct {
+	mu     sync.RWMutex
+	books  map[string]*Book
+	redis  redis.BookPublisher
+	logger *zap.SugaredLogger
+}
+
+// NewStore creates an empty book store.
+func NewStore(redisClient redis.BookPublisher, logger *zap.SugaredLogger) *Store {
+	return &Store{
+		books:  make(map[string]*Book),
+		redis:  redisClient,
+		logger: logger,
+	}
+}
+
+func (s *Store) bookFor(ticker string) *Book {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	book, ok := s.books[ticker]
+	if !ok {
+		book = NewBook(ticker)
+		s.books[ticker] = book
+	}
+	return book
+}
+
+// HandleSnapshot applies a full snapshot to the named ticker's book and
+// publishes the resulting state to the market_books stream.
+func (s *Store) HandleSnapshot(snap *models.OrderbookSnapshot) error {
+	book := s.bookFor(snap.Ticker)
+	if err := book.ApplySnapshot(snap); err != nil {
+		return err
+	}
+	return s.redis.PublishBookSnapshot(book.GetSnapshot())
+}
+
+// HandleDelta applies an incremental update to the named ticker's book and
+// returns any derived ticks. A sequence gap is returned as an error; for
+// Kalshi, connectors.KalshiHandler records the ticker in its
+// pendingResyncs set on this error, and connectors.Transport drains it via
+// PendingResyncs to force a resubscribe (which makes Kalshi re-push a
+// snapshot). The book is marked invalid in the meantime via Book.IsValid.
+func (s *Store) HandleDelta(delta *models.OrderbookDelta) ([]*models.Tick, error) {
+	book := s.bookFor(delta.Ticker)
+	ticks, err := book.ApplyDelta(delta)
+	if err != nil {
+		s.logger.Warnf("Sequence gap on %s, book invalid until next snapshot: %v", delta.Ticker, err)
+		return nil, err
+	}
+	return ticks, nil
+}
+
+// GetSnapshot returns the current state of a ticker's book, if one exists.
+func (s *Store) GetSnapshot(ticker string) (*models.OrderbookSnapshot, bool) {
+	s.mu.RLock()
+	book, ok := s.books[ticker]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return book.GetSnapshot(), true
+}
+
+// IsValid reports whether a ticker's book is currently consistent. A ticker
+// with no book yet is reported invalid.
+func (s *Store) IsValid(ticker string) bool {
+	s.mu.RLock()
+	book, ok := s.books[ticker]
+	s.mu.RUnlock()
+	if !ok {
+		return false
+	}
+	return book.IsValid()
+}
@@ -3,6 +3,7 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -17,46 +18,188 @@ const (
 	StreamName     = "market_ticks"
 	StreamMaxLen   = 10000
 	PubSubPrefix   = "tick:"
+
+	// BookStreamName carries full orderbook snapshots, published whenever the
+	// orderbook subsystem applies or rebuilds a book, alongside the regular
+	// per-tick stream.
+	BookStreamName = "market_books"
+	BookStreamMaxLen = 1000
 )
 
-// Client wraps Redis client with domain-specific methods
+// TickPublisher is the subset of Client that connectors depend on to publish
+// ticks. Accepting this instead of *Client lets conformance replay and other
+// tests substitute an in-memory fake.
+type TickPublisher interface {
+	PublishTick(tick *models.Tick) error
+}
+
+// ContractMetaPublisher is the subset of Client handlers depend on to
+// publish contract metadata at subscribe time.
+type ContractMetaPublisher interface {
+	PublishContractMeta(source, ticker string, info *models.ContractInfo) error
+}
+
+// BookPublisher is the subset of Client the orderbook subsystem depends on to
+// publish full book snapshots.
+type BookPublisher interface {
+	PublishBookSnapshot(snap *models.OrderbookSnapshot) error
+}
+
+// Publisher is the full set of Redis operations a connector needs: per-tick
+// publishing via its Transport, one-time contract metadata via its Handler at
+// subscribe time, and full book snapshots via its orderbook.Store.
+type Publisher interface {
+	TickPublisher
+	ContractMetaPublisher
+	BookPublisher
+}
+
+// Client wraps Redis client with domain-specific methods. rdb is a
+// redis.UniversalClient so a single-node *redis.Client, *redis.ClusterClient,
+// *redis.Ring, or a Sentinel-backed failover client are all interchangeable
+// behind the same domain methods below.
 type Client struct {
-	rdb    *redis.Client
-	logger *zap.SugaredLogger
-	ctx    context.Context
+	rdb          redis.UniversalClient
+	mode         string
+	streamMaxLen int64
+	logger       *zap.SugaredLogger
+	ctx          context.Context
+
+	// prefix namespaces every key/stream/channel this Client touches, so a
+	// SubscriptionGroup's feed never collides with another group's sharing
+	// the same Redis. Empty for the default (ungrouped) Client.
+	prefix string
 }
 
-// NewClient creates a new Redis client
+// NewClient creates a new Redis client. cfg.Mode selects the topology:
+//   - "single" (default): one node, dialed from Host/Port
+//   - "cluster": a Redis Cluster, dialed from Addrs
+//   - "sentinel": a Sentinel-monitored failover group; Addrs are the sentinel
+//     addresses and MasterName names the monitored master
+//   - "ring": a client-side sharded ring across Addrs, so high-throughput
+//     writes can scale horizontally across nodes instead of bottlenecking on one
 func NewClient(cfg config.RedisConfig, logger *zap.SugaredLogger) (*Client, error) {
-	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
-	
-	rdb := redis.NewClient(&redis.Options{
-		Addr:         addr,
-		Password:     cfg.Password,
-		DB:           cfg.DB,
-		PoolSize:     cfg.PoolSize,
-		MinIdleConns: cfg.MinIdleConns,
-		DialTimeout:  5 * time.Second,
-		ReadTimeout:  3 * time.Second,
-		WriteTimeout: 3 * time.Second,
-		PoolTimeout:  4 * time.Second,
-	})
+	rdb, describe, err := newUniversalClient(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	// Test connection
 	ctx := context.Background()
 	if err := rdb.Ping(ctx).Err(); err != nil {
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
-	logger.Infof("Connected to Redis at %s", addr)
+	logger.Infof("Connected to Redis (%s mode) at %s", cfg.Mode, describe)
+
+	streamMaxLen := cfg.StreamMaxLen
+	if streamMaxLen == 0 {
+		streamMaxLen = StreamMaxLen
+	}
 
 	return &Client{
-		rdb:    rdb,
-		logger: logger,
-		ctx:    ctx,
+		rdb:          rdb,
+		mode:         cfg.Mode,
+		streamMaxLen: streamMaxLen,
+		logger:       logger,
+		ctx:          ctx,
 	}, nil
 }
 
+// ForGroup returns a Client namespaced to a SubscriptionGroup's Redis key
+// prefix. It shares the same underlying rdb connection pool as c and opens
+// no connection of its own, so a multi-tenant process uses one pool for
+// every group. An empty prefix (the default group) returns a Client
+// byte-for-byte equivalent to c.
+func (c *Client) ForGroup(prefix string) *Client {
+	grouped := *c
+	grouped.prefix = prefix
+	return &grouped
+}
+
+// key namespaces name under c.prefix, so each SubscriptionGroup's streams,
+// channels, and hash keys live in their own keyspace within a shared Redis.
+func (c *Client) key(name string) string {
+	if c.prefix == "" {
+		return name
+	}
+	return fmt.Sprintf("%s:%s", c.prefix, name)
+}
+
+func newUniversalClient(cfg config.RedisConfig) (redis.UniversalClient, string, error) {
+	switch cfg.Mode {
+	case "", "single":
+		addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+		return redis.NewClient(&redis.Options{
+			Addr:         addr,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			DialTimeout:  5 * time.Second,
+			ReadTimeout:  3 * time.Second,
+			WriteTimeout: 3 * time.Second,
+			PoolTimeout:  4 * time.Second,
+		}), addr, nil
+
+	case "cluster":
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Password:     cfg.Password,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			DialTimeout:  5 * time.Second,
+			ReadTimeout:  3 * time.Second,
+			WriteTimeout: 3 * time.Second,
+			PoolTimeout:  4 * time.Second,
+		}), fmt.Sprintf("%v", cfg.Addrs), nil
+
+	case "sentinel":
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    cfg.MasterName,
+			SentinelAddrs: cfg.Addrs,
+			Password:      cfg.Password,
+			DB:            cfg.DB,
+			PoolSize:      cfg.PoolSize,
+			MinIdleConns:  cfg.MinIdleConns,
+			DialTimeout:   5 * time.Second,
+			ReadTimeout:   3 * time.Second,
+			WriteTimeout:  3 * time.Second,
+			PoolTimeout:   4 * time.Second,
+		}), fmt.Sprintf("sentinel master %q via %v", cfg.MasterName, cfg.Addrs), nil
+
+	case "ring":
+		shards := make(map[string]string, len(cfg.Addrs))
+		for i, addr := range cfg.Addrs {
+			shards[fmt.Sprintf("shard%d", i)] = addr
+		}
+		return redis.NewRing(&redis.RingOptions{
+			Addrs:        shards,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+			DialTimeout:  5 * time.Second,
+			ReadTimeout:  3 * time.Second,
+			WriteTimeout: 3 * time.Second,
+			PoolTimeout:  4 * time.Second,
+		}), fmt.Sprintf("%v", cfg.Addrs), nil
+
+	default:
+		return nil, "", fmt.Errorf("unknown redis mode %q", cfg.Mode)
+	}
+}
+
+// streamKey returns the stream PublishTick writes to for a contract. In ring
+// mode it's suffixed with the contract ID so the Ring's consistent hashing
+// shards writes per-contract across nodes instead of funneling every tick
+// through whichever node happens to own the bare "market_ticks" key.
+func (c *Client) streamKey(contractID string) string {
+	if c.mode == "ring" {
+		return c.key(fmt.Sprintf("%s:%s", StreamName, contractID))
+	}
+	return c.key(StreamName)
+}
+
 // PublishTick publishes a tick to Redis Stream and Pub/Sub
 func (c *Client) PublishTick(tick *models.Tick) error {
 	// Validate tick
@@ -75,8 +218,8 @@ func (c *Client) PublishTick(tick *models.Tick) error {
 
 	// Add to Stream
 	pipe.XAdd(c.ctx, &redis.XAddArgs{
-		Stream: StreamName,
-		MaxLen: StreamMaxLen,
+		Stream: c.streamKey(tick.ContractID),
+		MaxLen: c.streamMaxLen,
 		Approx: true, // Use approximate trimming for performance
 		Values: map[string]interface{}{
 			"data": data,
@@ -84,7 +227,7 @@ func (c *Client) PublishTick(tick *models.Tick) error {
 	})
 
 	// Publish to Pub/Sub channel
-	channel := fmt.Sprintf("%s%s", PubSubPrefix, tick.ContractID)
+	channel := c.key(fmt.Sprintf("%s%s", PubSubPrefix, tick.ContractID))
 	pipe.Publish(c.ctx, channel, data)
 
 	// Execute pipeline
@@ -96,10 +239,45 @@ func (c *Client) PublishTick(tick *models.Tick) error {
 	return nil
 }
 
-// GetLatestTicks retrieves the last N ticks from the stream
+// PublishContractMeta publishes a contract's tick-size/quote metadata to a
+// per-ticker hash so downstream arb consumers can compute min-profitable
+// spreads instead of guessing.
+func (c *Client) PublishContractMeta(source, ticker string, info *models.ContractInfo) error {
+	key := c.key(fmt.Sprintf("contracts:meta:%s:%s", source, ticker))
+	return c.rdb.HSet(c.ctx, key, map[string]interface{}{
+		"ticker":          info.Ticker,
+		"price_tick_size": info.PriceTickSize,
+		"quote_currency":  info.QuoteCurrency,
+		"underlying":      info.Underlying,
+	}).Err()
+}
+
+// PublishBookSnapshot publishes a full orderbook snapshot to the
+// market_books stream, so downstream consumers can rebuild book state
+// without replaying the whole delta history.
+func (c *Client) PublishBookSnapshot(snap *models.OrderbookSnapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("json marshal error: %w", err)
+	}
+
+	return c.rdb.XAdd(c.ctx, &redis.XAddArgs{
+		Stream: c.key(BookStreamName),
+		MaxLen: BookStreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"data": data,
+		},
+	}).Err()
+}
+
+// GetLatestTicks retrieves the last N ticks from the stream. It reads only
+// the unsharded "market_ticks" key; in ring mode PublishTick writes to
+// per-contract streams instead (see streamKey), so ticks published in ring
+// mode are not returned here.
 func (c *Client) GetLatestTicks(count int64) ([]models.Tick, error) {
 	// Read from stream
-	messages, err := c.rdb.XRevRangeN(c.ctx, StreamName, "+", "-", count).Result()
+	messages, err := c.rdb.XRevRangeN(c.ctx, c.key(StreamName), "+", "-", count).Result()
 	if err != nil {
 		return nil, fmt.Errorf("redis xrevrange error: %w", err)
 	}
@@ -126,12 +304,20 @@ func (c *Client) GetLatestTicks(count int64) ([]models.Tick, error) {
 
 // GetStreamLength returns the number of messages in the stream
 func (c *Client) GetStreamLength() (int64, error) {
-	return c.rdb.XLen(c.ctx, StreamName).Result()
+	return c.rdb.XLen(c.ctx, c.key(StreamName)).Result()
 }
 
 // TrimStream trims the stream to the specified length
 func (c *Client) TrimStream(maxLen int64) error {
-	return c.rdb.XTrimMaxLen(c.ctx, StreamName, maxLen).Err()
+	return c.rdb.XTrimMaxLen(c.ctx, c.key(StreamName), maxLen).Err()
+}
+
+// TrimStreamMinID trims the stream to retain only entries with an ID >=
+// minID, for time-based retention instead of TrimStream's count-based one.
+// minID is a stream ID or a millisecond timestamp, e.g.
+// fmt.Sprintf("%d", time.Now().Add(-24*time.Hour).UnixMilli()).
+func (c *Client) TrimStreamMinID(minID string) error {
+	return c.rdb.XTrimMinID(c.ctx, c.key(StreamName), minID).Err()
 }
 
 // SubscribeToTicks creates a subscription to tick updates
@@ -140,6 +326,15 @@ func (c *Client) SubscribeToTicks(pattern string) *redis.PubSub {
 	return c.rdb.PSubscribe(c.ctx, pattern)
 }
 
+// Subscribe subscribes to a single exact channel, e.g. a control-plane
+// channel like "manifold:control" rather than a tick-stream pattern. channel
+// is namespaced through c.key() like every other method on Client, so two
+// SubscriptionGroups sharing one Redis instance via ForGroup don't also
+// share control-plane messages meant for only one of them.
+func (c *Client) Subscribe(channel string) *redis.PubSub {
+	return c.rdb.Subscribe(c.ctx, c.key(channel))
+}
+
 // SetValue sets a simple key-value pair
 func (c *Client) SetValue(key string, value interface{}, expiration time.Duration) error {
 	return c.rdb.Set(c.ctx, key, value, expiration).Err()
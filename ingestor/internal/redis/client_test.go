@@ -0,0 +1,136 @@
+package redis
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/dragonuber/echoarb/ingestor/internal/config"
+	"github.com/dragonuber/echoarb/ingestor/internal/models"
+	"go.uber.org/zap"
+)
+
+// testClient starts a miniredis instance and a single-mode Client pointed
+// at it, so these tests exercise the real pipeline/stream/pub-sub commands
+// without a live Redis.
+func testClient(t *testing.T) (*Client, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	host, portStr, err := net.SplitHostPort(mr.Addr())
+	if err != nil {
+		t.Fatalf("split miniredis addr: %v", err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatalf("parse miniredis port: %v", err)
+	}
+
+	c, err := NewClient(config.RedisConfig{Mode: "single", Host: host, Port: port}, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c, mr
+}
+
+func TestClientPublishTick(t *testing.T) {
+	c, mr := testClient(t)
+
+	tick := &models.Tick{Source: "KALSHI", ContractID: "INXD-24DEC31-B5000", Price: 0.63, TimestampSource: 1, TimestampIngest: 2}
+	if err := c.PublishTick(tick); err != nil {
+		t.Fatalf("PublishTick: %v", err)
+	}
+
+	entries, err := mr.Stream(StreamName)
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected 1 stream entry, got %d", len(entries))
+	}
+}
+
+func TestClientPublishContractMeta(t *testing.T) {
+	c, mr := testClient(t)
+
+	info := &models.ContractInfo{Ticker: "INXD-24DEC31-B5000", PriceTickSize: 0.01, QuoteCurrency: "USD"}
+	if err := c.PublishContractMeta("KALSHI", "INXD-24DEC31-B5000", info); err != nil {
+		t.Fatalf("PublishContractMeta: %v", err)
+	}
+
+	if !mr.Exists("contracts:meta:KALSHI:INXD-24DEC31-B5000") {
+		t.Error("expected contract meta hash to exist")
+	}
+}
+
+// TestClientForGroupNamespacesKeys confirms two groups sharing one miniredis
+// instance via ForGroup publish into disjoint streams - the whole point of
+// SubscriptionGroup.RedisPrefix.
+func TestClientForGroupNamespacesKeys(t *testing.T) {
+	c, mr := testClient(t)
+	tenantA := c.ForGroup("tenant-a")
+	tenantB := c.ForGroup("tenant-b")
+
+	tick := &models.Tick{Source: "KALSHI", ContractID: "INXD-24DEC31-B5000", Price: 0.63, TimestampSource: 1, TimestampIngest: 2}
+	if err := tenantA.PublishTick(tick); err != nil {
+		t.Fatalf("PublishTick (tenant-a): %v", err)
+	}
+
+	aEntries, err := mr.Stream("tenant-a:" + StreamName)
+	if err != nil {
+		t.Fatalf("Stream tenant-a: %v", err)
+	}
+	if len(aEntries) != 1 {
+		t.Errorf("expected 1 entry in tenant-a's stream, got %d", len(aEntries))
+	}
+
+	if mr.Exists("tenant-b:" + StreamName) {
+		t.Error("tenant-b's stream should not exist after only tenant-a published")
+	}
+	_ = tenantB
+}
+
+// TestClientSubscribeNamespacesChannel confirms Subscribe prefixes the
+// channel name through ForGroup's c.key() like every other pub/sub and
+// stream method on Client - otherwise every SubscriptionGroup's
+// ManifoldConnector would end up subscribed to the exact same raw channel
+// name, and one tenant's control message would be delivered to every other
+// tenant's connector.
+func TestClientSubscribeNamespacesChannel(t *testing.T) {
+	c, mr := testClient(t)
+	tenantA := c.ForGroup("tenant-a")
+
+	sub := tenantA.Subscribe("manifold:control")
+	defer sub.Close()
+	if _, err := sub.Receive(context.Background()); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	if _, err := mr.Publish("manifold:control", "should not be received"); err != nil {
+		t.Fatalf("publish to unprefixed channel: %v", err)
+	}
+	if _, err := mr.Publish("tenant-a:manifold:control", "hello"); err != nil {
+		t.Fatalf("publish to tenant-a:manifold:control: %v", err)
+	}
+
+	select {
+	case msg := <-sub.Channel():
+		if msg.Payload != "hello" {
+			t.Errorf("expected payload %q, got %q", "hello", msg.Payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for message on the namespaced channel")
+	}
+}
+
+// TestNewUniversalClientUnknownMode confirms an unrecognized Mode fails fast
+// with a clear error, instead of silently dialing a single-node client.
+func TestNewUniversalClientUnknownMode(t *testing.T) {
+	_, _, err := newUniversalClient(config.RedisConfig{Mode: "bogus"})
+	if err == nil {
+		t.Fatal("expected an error for an unknown redis mode")
+	}
+}
@@ -0,0 +1,257 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/dragonuber/echoarb/ingestor/internal/models"
+	"github.com/dragonuber/echoarb/ingestor/internal/retry"
+	"go.uber.org/zap"
+)
+
+// DeadLetterStreamName receives ticks whose handler failed
+// ConsumerConfig.MaxDeliveries times in a row.
+const DeadLetterStreamName = "market_ticks:dead"
+
+// ConsumerConfig configures a consumer-group reader over a stream.
+type ConsumerConfig struct {
+	Stream       string // defaults to StreamName
+	Group        string
+	ConsumerName string
+
+	// IdleTimeout is how long a message can sit pending before Run reclaims
+	// it from whatever consumer holds it via XCLAIM.
+	IdleTimeout time.Duration
+	// MaxDeliveries is how many failed handler attempts a message gets
+	// before Run dead-letters it instead of retrying again.
+	MaxDeliveries int
+	// BlockTimeout is how long a single XREADGROUP call blocks for new
+	// messages before looping back to check for idle messages to reclaim.
+	BlockTimeout time.Duration
+	// Backoff configures the retry delay between failed handler attempts.
+	Backoff retry.Config
+}
+
+func (cfg *ConsumerConfig) setDefaults() {
+	if cfg.Stream == "" {
+		cfg.Stream = StreamName
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = 30 * time.Second
+	}
+	if cfg.MaxDeliveries <= 0 {
+		cfg.MaxDeliveries = 5
+	}
+	if cfg.BlockTimeout <= 0 {
+		cfg.BlockTimeout = 5 * time.Second
+	}
+	if cfg.Backoff.InitialInterval <= 0 {
+		cfg.Backoff.InitialInterval = 200 * time.Millisecond
+	}
+	if cfg.Backoff.MaxInterval <= 0 {
+		cfg.Backoff.MaxInterval = 5 * time.Second
+	}
+	if cfg.Backoff.Multiplier <= 0 {
+		cfg.Backoff.Multiplier = 2.0
+	}
+}
+
+// Consumer reads market_ticks via a Redis Streams consumer group, giving
+// downstream arb strategies at-least-once delivery: a crashed consumer
+// resumes unacked messages from its group position, and messages stuck on a
+// dead consumer are reclaimed by another one after IdleTimeout.
+type Consumer struct {
+	client *Client
+	cfg    ConsumerConfig
+	logger *zap.SugaredLogger
+}
+
+// NewConsumer creates the consumer group (idempotently - it's fine if it
+// already exists) and returns a Consumer ready to Run.
+func NewConsumer(client *Client, cfg ConsumerConfig, logger *zap.SugaredLogger) (*Consumer, error) {
+	cfg.setDefaults()
+	if cfg.Group == "" {
+		return nil, fmt.Errorf("consumer group name is required")
+	}
+	if cfg.ConsumerName == "" {
+		return nil, fmt.Errorf("consumer name is required")
+	}
+
+	c := &Consumer{client: client, cfg: cfg, logger: logger}
+	if err := c.ensureGroup(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ensureGroup creates the consumer group starting from the beginning of the
+// stream (and the stream itself, if it doesn't exist yet). BUSYGROUP means
+// the group already exists, which is the expected case on every restart
+// after the first.
+func (c *Consumer) ensureGroup() error {
+	err := c.client.rdb.XGroupCreateMkStream(c.client.ctx, c.cfg.Stream, c.cfg.Group, "0").Err()
+	if err != nil && !strings.Contains(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("xgroup create: %w", err)
+	}
+	return nil
+}
+
+// Run reads ticks from the consumer group and hands each to handler, ACKing
+// on success. A failing handler is retried with the package retry.Backoff up
+// to cfg.MaxDeliveries times, then the tick is moved to
+// DeadLetterStreamName and ACKed so it doesn't block the group. Run also
+// reclaims messages idle longer than cfg.IdleTimeout from dead consumers.
+// It blocks until ctx is canceled.
+func (c *Consumer) Run(ctx context.Context, handler func(*models.Tick) error) error {
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := c.reclaimIdle(ctx, handler); err != nil {
+			c.logger.Warnf("[%s/%s] Failed to reclaim idle messages: %v", c.cfg.Group, c.cfg.ConsumerName, err)
+		}
+
+		streams, err := c.client.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.cfg.Group,
+			Consumer: c.cfg.ConsumerName,
+			Streams:  []string{c.cfg.Stream, ">"},
+			Count:    100,
+			Block:    c.cfg.BlockTimeout,
+		}).Result()
+		if err != nil {
+			if err == redis.Nil {
+				continue // no new messages within BlockTimeout
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("xreadgroup: %w", err)
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				c.handle(ctx, msg, handler)
+			}
+		}
+	}
+}
+
+// reclaimIdle finds messages pending longer than IdleTimeout for any
+// consumer in the group, claims them for this consumer, and processes them.
+func (c *Consumer) reclaimIdle(ctx context.Context, handler func(*models.Tick) error) error {
+	pending, err := c.client.rdb.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: c.cfg.Stream,
+		Group:  c.cfg.Group,
+		Idle:   c.cfg.IdleTimeout,
+		Start:  "-",
+		End:    "+",
+		Count:  100,
+	}).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil
+		}
+		return fmt.Errorf("xpending: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(pending))
+	for i, p := range pending {
+		ids[i] = p.ID
+	}
+
+	claimed, err := c.client.rdb.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   c.cfg.Stream,
+		Group:    c.cfg.Group,
+		Consumer: c.cfg.ConsumerName,
+		MinIdle:  c.cfg.IdleTimeout,
+		Messages: ids,
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("xclaim: %w", err)
+	}
+
+	for _, msg := range claimed {
+		c.handle(ctx, msg, handler)
+	}
+	return nil
+}
+
+// handle decodes one message and drives it through handler with bounded
+// retries, ACKing on success and dead-lettering after MaxDeliveries.
+func (c *Consumer) handle(ctx context.Context, msg redis.XMessage, handler func(*models.Tick) error) {
+	tick, err := decodeTickMessage(msg)
+	if err != nil {
+		c.logger.Warnf("[%s/%s] Failed to decode %s, dead-lettering: %v", c.cfg.Group, c.cfg.ConsumerName, msg.ID, err)
+		c.deadLetter(ctx, msg, err)
+		c.ack(ctx, msg.ID)
+		return
+	}
+
+	backoff := retry.NewBackoff(c.cfg.Backoff)
+	var handlerErr error
+	for attempt := 0; attempt < c.cfg.MaxDeliveries; attempt++ {
+		if handlerErr = handler(tick); handlerErr == nil {
+			c.ack(ctx, msg.ID)
+			return
+		}
+
+		c.logger.Warnf("[%s/%s] Handler failed for %s (attempt %d/%d): %v", c.cfg.Group, c.cfg.ConsumerName, msg.ID, attempt+1, c.cfg.MaxDeliveries, handlerErr)
+		select {
+		case <-time.After(backoff.Next(attempt)):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	c.deadLetter(ctx, msg, handlerErr)
+	c.ack(ctx, msg.ID)
+}
+
+func (c *Consumer) ack(ctx context.Context, id string) {
+	if err := c.client.rdb.XAck(ctx, c.cfg.Stream, c.cfg.Group, id).Err(); err != nil {
+		c.logger.Warnf("[%s/%s] Failed to ack %s: %v", c.cfg.Group, c.cfg.ConsumerName, id, err)
+	}
+}
+
+// deadLetter copies a message's raw payload, plus the reason it was given up
+// on, to DeadLetterStreamName so it isn't silently dropped.
+func (c *Consumer) deadLetter(ctx context.Context, msg redis.XMessage, cause error) {
+	causeMsg := ""
+	if cause != nil {
+		causeMsg = cause.Error()
+	}
+
+	err := c.client.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: DeadLetterStreamName,
+		Values: map[string]interface{}{
+			"data":        msg.Values["data"],
+			"error":       causeMsg,
+			"original_id": msg.ID,
+			"source_stream": c.cfg.Stream,
+		},
+	}).Err()
+	if err != nil {
+		c.logger.Errorf("[%s/%s] Failed to dead-letter %s: %v", c.cfg.Group, c.cfg.ConsumerName, msg.ID, err)
+	}
+}
+
+func decodeTickMessage(msg redis.XMessage) (*models.Tick, error) {
+	data, ok := msg.Values["data"].(string)
+	if !ok {
+		return nil, fmt.Errorf("message %s missing data field", msg.ID)
+	}
+
+	var tick models.Tick
+	if err := msgpack.Unmarshal([]byte(data), &tick); err != nil {
+		return nil, fmt.Errorf("msgpack unmarshal error: %w", err)
+	}
+	return &tick, nil
+}
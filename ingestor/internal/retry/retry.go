@@ -2,23 +2,123 @@ package retry
 
 import (
 	"context"
+	"math"
 	"math/rand"
 	"sync"
 	"time"
 	"go.uber.org/zap"
 )
 
+// JitterStrategy selects how Backoff randomizes sleep durations between
+// attempts, to avoid every connector reconnecting in lockstep after a shared
+// outage (e.g. all Kalshi/Polymarket sockets dropping together).
+type JitterStrategy string
+
+const (
+	// JitterNone sleeps for exactly the computed backoff interval.
+	JitterNone JitterStrategy = "none"
+	// JitterFull sleeps for a random duration in [0, min(cap, base*mult^attempt)].
+	JitterFull JitterStrategy = "full"
+	// JitterDecorrelated is the AWS-recommended decorrelated-jitter
+	// recurrence: sleep = min(cap, rand(base, prev*3)).
+	JitterDecorrelated JitterStrategy = "decorrelated"
+)
+
 type Config struct {
 	InitialInterval time.Duration
 	MaxInterval     time.Duration
 	MaxRetries      int // 0 = infinite
 	Multiplier      float64
-	Jitter          bool
+
+	// Jitter is the legacy Â±25% additive jitter; ignored if JitterStrategy is set.
+	Jitter bool
+	// JitterStrategy selects one of JitterNone/JitterFull/JitterDecorrelated.
+	// Left empty, RetryForever and Backoff fall back to the legacy Jitter bool.
+	JitterStrategy JitterStrategy
+
+	// Breaker, if set, is consulted by RetryForever before every attempt;
+	// while it reports the circuit open, fn() is skipped entirely instead of
+	// hammering a failing endpoint.
+	Breaker *CircuitBreaker
+}
+
+// Backoff is a stateful exponential-backoff policy. RetryForever owns one
+// internally, but it's exported so callers that need their own reconnect
+// loop outside RetryForever (e.g. a websocket connector driving its own
+// read/reconnect cycle) can reuse the same jitter strategy.
+type Backoff struct {
+	cfg  Config
+	prev time.Duration
+}
+
+// NewBackoff creates a Backoff from cfg, starting from InitialInterval.
+func NewBackoff(cfg Config) *Backoff {
+	return &Backoff{cfg: cfg}
+}
+
+// Next returns the sleep duration for the given attempt (0-indexed) and
+// records it so JitterDecorrelated can use it for the next call's upper bound
+// (prev*3).
+func (b *Backoff) Next(attempt int) time.Duration {
+	base := time.Duration(float64(b.cfg.InitialInterval) * math.Pow(b.cfg.Multiplier, float64(attempt)))
+	if base > b.cfg.MaxInterval {
+		base = b.cfg.MaxInterval
+	}
+
+	var sleep time.Duration
+	switch b.cfg.JitterStrategy {
+	case JitterNone:
+		sleep = base
+
+	case JitterFull:
+		sleep = time.Duration(rand.Int63n(int64(base) + 1))
+
+	case JitterDecorrelated:
+		prev := b.prev
+		if prev == 0 {
+			prev = b.cfg.InitialInterval
+		}
+		lower := b.cfg.InitialInterval
+		upper := prev * 3
+		if upper > b.cfg.MaxInterval {
+			upper = b.cfg.MaxInterval
+		}
+		if upper <= lower {
+			sleep = lower
+		} else {
+			sleep = lower + time.Duration(rand.Int63n(int64(upper-lower)+1))
+		}
+
+	default:
+		// Legacy Â±25% additive jitter.
+		sleep = base
+		if b.cfg.Jitter {
+			jitter := time.Duration(float64(sleep) * 0.25 * (2*rand.Float64() - 1))
+			sleep += jitter
+		}
+	}
+
+	if sleep > b.cfg.MaxInterval {
+		sleep = b.cfg.MaxInterval
+	}
+	if sleep < 0 {
+		sleep = 0
+	}
+
+	b.prev = sleep
+	return sleep
+}
+
+// Reset clears accumulated state (e.g. after a successful connection), so the
+// next Next() call starts from InitialInterval again.
+func (b *Backoff) Reset() {
+	b.prev = 0
 }
 
-// RetryForever executes a function indefinitely with exponential backoff
+// RetryForever executes a function indefinitely with exponential backoff. If
+// cfg.Breaker is set, fn() is skipped while the circuit is open.
 func RetryForever(ctx context.Context, cfg Config, logger *zap.SugaredLogger, name string, fn func() error) {
-	interval := cfg.InitialInterval
+	backoff := NewBackoff(cfg)
 	attempt := 0
 
 	for {
@@ -26,28 +126,35 @@ func RetryForever(ctx context.Context, cfg Config, logger *zap.SugaredLogger, na
 			return
 		}
 
+		if cfg.Breaker != nil && !cfg.Breaker.Allow() {
+			interval := backoff.Next(attempt)
+			logger.Warnf("[%s] Circuit breaker open, skipping attempt. Retrying in %v", name, interval)
+			select {
+			case <-time.After(interval):
+				attempt++
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
 		err := fn()
+		if cfg.Breaker != nil {
+			cfg.Breaker.RecordResult(err == nil)
+		}
+
 		if err == nil {
 			// Reset on success
-			interval = cfg.InitialInterval
+			backoff.Reset()
 			attempt = 0
 			continue
 		}
 
+		interval := backoff.Next(attempt)
 		logger.Warnf("[%s] Connection failed: %v. Retrying in %v", name, err, interval)
 
 		select {
 		case <-time.After(interval):
-			// Calculate next interval: min(current * multiplier, max)
-			interval = time.Duration(float64(interval) * cfg.Multiplier)
-			if interval > cfg.MaxInterval {
-				interval = cfg.MaxInterval
-			}
-			// Add Jitter (Â±25%)
-			if cfg.Jitter {
-				jitter := time.Duration(float64(interval) * 0.25 * (2*rand.Float64() - 1))
-				interval += jitter
-			}
 			attempt++
 		case <-ctx.Done():
 			return
@@ -101,4 +208,4 @@ func (cb *CircuitBreaker) RecordResult(success bool) {
 			cb.lastFail = time.Now()
 		}
 	}
-}
\ No newline at end of file
+}